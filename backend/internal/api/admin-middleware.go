@@ -0,0 +1,23 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware only lets through requests with the admin API key as bearer token. The admin routes write data that
+// is shared by every user, like the fx rates. Without an API key, every request is rejected.
+func AdminMiddleware(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			fmt.Printf("invalid admin API key for %s\n", c.Request.URL.Path)
+			c.JSON(401, NewError(401, "Unauthorized"))
+			c.Abort()
+			return
+		}
+	}
+}
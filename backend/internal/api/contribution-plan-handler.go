@@ -0,0 +1,287 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/slices"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type ContributionPlanHandler struct {
+	contributionPlanService services.ContributionPlanService
+	investmentService       services.InvestmentService
+	portfolioService        services.PortfolioService
+}
+
+func NewContributionPlanHandler(
+	contributionPlanService services.ContributionPlanService,
+	investmentService services.InvestmentService,
+	portfolioService services.PortfolioService,
+) ContributionPlanHandler {
+	return ContributionPlanHandler{
+		contributionPlanService: contributionPlanService,
+		investmentService:       investmentService,
+		portfolioService:        portfolioService,
+	}
+}
+
+func (h ContributionPlanHandler) GetContributionPlans(c *gin.Context) (response[[]contributionPlanDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]contributionPlanDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]contributionPlanDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	plans, err := h.contributionPlanService.FindByInvestmentIDs(
+		slices.Map(investments, func(i domain.Investment) string { return i.ID }),
+	)
+	if err != nil {
+		return response[[]contributionPlanDto]{}, fmt.Errorf("failed to find contribution plans: %w", err)
+	}
+
+	return newResponse(http.StatusOK, slices.Map(plans, toContributionPlanDto)), nil
+}
+
+func (h ContributionPlanHandler) CreateContributionPlan(c *gin.Context) (response[contributionPlanDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request createContributionPlanRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[contributionPlanDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	command, err := request.toCommand()
+	if err != nil {
+		return response[contributionPlanDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[contributionPlanDto]{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return response[contributionPlanDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[contributionPlanDto]{}, NewError(http.StatusForbidden, "not allowed to create contribution plan")
+	}
+
+	plan, err := h.contributionPlanService.Create(investment, command)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContributionPlan) || errors.Is(err, domain.ErrInvestmentIsClosed) {
+			return response[contributionPlanDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[contributionPlanDto]{}, fmt.Errorf("failed to create contribution plan: %w", err)
+	}
+
+	return newResponse(http.StatusCreated, toContributionPlanDto(plan)), nil
+}
+
+func (h ContributionPlanHandler) PatchContributionPlan(c *gin.Context) (response[contributionPlanDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request patchContributionPlanRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[contributionPlanDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	command := domain.UpdateContributionPlanCommand{
+		Amount:     request.Amount,
+		DayOfMonth: request.DayOfMonth,
+		Paused:     request.Paused,
+	}
+	if request.Frequency != nil {
+		frequency := domain.ContributionFrequency(*request.Frequency)
+		command.Frequency = &frequency
+	}
+
+	plan, err := h.findOwnedContributionPlan(c.Param("id"), tokenUserID, "not allowed to update contribution plan")
+	if err != nil {
+		return response[contributionPlanDto]{}, err
+	}
+
+	updated, err := h.contributionPlanService.Update(plan, command)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContributionPlan) {
+			return response[contributionPlanDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[contributionPlanDto]{}, fmt.Errorf("failed to update contribution plan: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toContributionPlanDto(updated)), nil
+}
+
+// SkipContributionPlan skips the run in the month of the optional date in the body, which defaults to the month of
+// the next run.
+func (h ContributionPlanHandler) SkipContributionPlan(c *gin.Context) (response[contributionPlanDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request skipContributionPlanRequest
+	if c.Request.ContentLength > 0 {
+		err := c.ShouldBindJSON(&request)
+		if err != nil {
+			return response[contributionPlanDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	plan, err := h.findOwnedContributionPlan(c.Param("id"), tokenUserID, "not allowed to update contribution plan")
+	if err != nil {
+		return response[contributionPlanDto]{}, err
+	}
+
+	month := plan.NextRunDate
+	if request.Month != nil {
+		month, err = time.Parse("2006-01", *request.Month)
+		if err != nil {
+			return response[contributionPlanDto]{}, NewError(http.StatusBadRequest, fmt.Sprintf("failed to parse month: %s", err.Error()))
+		}
+	}
+
+	updated, err := h.contributionPlanService.Skip(plan, month)
+	if err != nil {
+		return response[contributionPlanDto]{}, fmt.Errorf("failed to skip contribution plan: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toContributionPlanDto(updated)), nil
+}
+
+func (h ContributionPlanHandler) DeleteContributionPlan(c *gin.Context) (response[empty], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	plan, err := h.findOwnedContributionPlan(c.Param("id"), tokenUserID, "not allowed to delete contribution plan")
+	if err != nil {
+		return response[empty]{}, err
+	}
+
+	err = h.contributionPlanService.DeleteByID(plan.ID)
+	if err != nil {
+		return response[empty]{}, fmt.Errorf("failed to delete contribution plan: %w", err)
+	}
+
+	return newEmptyResponse(http.StatusNoContent), nil
+}
+
+// findOwnedContributionPlan finds the plan and checks that the investment it deposits into belongs to the user.
+func (h ContributionPlanHandler) findOwnedContributionPlan(id, userID, forbiddenMessage string) (domain.ContributionPlan, error) {
+	plan, err := h.contributionPlanService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrContributionPlanNotFound {
+			return domain.ContributionPlan{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.ContributionPlan{}, fmt.Errorf("failed to find contribution plan: %w", err)
+	}
+
+	investment, err := h.investmentService.FindByID(plan.InvestmentID)
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to find investment by id %s: %w", plan.InvestmentID, err)
+	}
+
+	if investment.UserID != userID {
+		return domain.ContributionPlan{}, NewError(http.StatusForbidden, forbiddenMessage)
+	}
+
+	return plan, nil
+}
+
+type createContributionPlanRequest struct {
+	Amount     int64   `json:"amount"`
+	Frequency  string  `json:"frequency"`
+	DayOfMonth int     `json:"dayOfMonth"`
+	StartDate  *string `json:"startDate"`
+}
+
+func (r createContributionPlanRequest) toCommand() (domain.SaveContributionPlanCommand, error) {
+	var startDate time.Time
+	if r.StartDate != nil {
+		parsed, err := time.Parse("2006-01-02", *r.StartDate)
+		if err != nil {
+			return domain.SaveContributionPlanCommand{}, fmt.Errorf("failed to parse startDate: %w", err)
+		}
+		startDate = parsed
+	}
+
+	return domain.NewSaveContributionPlanCommand(
+		r.Amount,
+		domain.ContributionFrequency(r.Frequency),
+		r.DayOfMonth,
+		startDate,
+	), nil
+}
+
+type patchContributionPlanRequest struct {
+	Amount     *int64  `json:"amount"`
+	Frequency  *string `json:"frequency"`
+	DayOfMonth *int    `json:"dayOfMonth"`
+	Paused     *bool   `json:"paused"`
+}
+
+type skipContributionPlanRequest struct {
+	Month *string `json:"month"`
+}
+
+func toContributionPlanDto(p domain.ContributionPlan) contributionPlanDto {
+	return newContributionPlanDto(
+		p.ID,
+		p.InvestmentID,
+		p.Amount,
+		p.Frequency,
+		p.DayOfMonth,
+		p.NextRunDate.Format("2006-01-02"),
+		p.Paused,
+		slices.Map(p.SkippedMonths, func(month time.Time) string { return month.Format("2006-01") }),
+	)
+}
+
+type contributionPlanDto struct {
+	ID            string                       `json:"id"`
+	InvestmentID  string                       `json:"investmentId"`
+	Amount        int64                        `json:"amount"`
+	Frequency     domain.ContributionFrequency `json:"frequency"`
+	DayOfMonth    int                          `json:"dayOfMonth"`
+	NextRunDate   string                       `json:"nextRunDate"`
+	Paused        bool                         `json:"paused"`
+	SkippedMonths []string                     `json:"skippedMonths"`
+}
+
+func newContributionPlanDto(
+	id,
+	investmentID string,
+	amount int64,
+	frequency domain.ContributionFrequency,
+	dayOfMonth int,
+	nextRunDate string,
+	paused bool,
+	skippedMonths []string,
+) contributionPlanDto {
+	return contributionPlanDto{
+		ID:            id,
+		InvestmentID:  investmentID,
+		Amount:        amount,
+		Frequency:     frequency,
+		DayOfMonth:    dayOfMonth,
+		NextRunDate:   nextRunDate,
+		Paused:        paused,
+		SkippedMonths: skippedMonths,
+	}
+}
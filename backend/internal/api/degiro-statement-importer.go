@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/csv"
+	"growfolio/internal/domain"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The columns of the account CSV of DEGIRO, which has no headers for the amounts next to the currencies.
+const (
+	degiroColumnDate            = 0
+	degiroColumnTime            = 1
+	degiroColumnISIN            = 4
+	degiroColumnDescription     = 5
+	degiroColumnFX              = 6
+	degiroColumnChangeCurrency  = 7
+	degiroColumnChange          = 8
+	degiroColumnBalanceCurrency = 9
+	degiroColumnBalance         = 10
+	degiroColumnOrderID         = 11
+)
+
+// degiroTradePattern matches trade descriptions like "Buy 8 Vanguard FTSE All-World@105,20 EUR (IE00BK5BQT80)", in
+// English and in Dutch.
+var degiroTradePattern = regexp.MustCompile(`(?i)^(buy|sell|koop|verkoop) ([\d.,]+) .*@([\d.,]+) ([A-Z]{3})`)
+
+// DEGIROStatementImporter reads the account CSV of DEGIRO, in which the newest rows come first. The currency of the
+// account is the currency of the deposits. The account has no market prices, so it is valued at the cash balance plus
+// the positions. Trades in another currency are converted at the rate of the currency exchange of the same order.
+type DEGIROStatementImporter struct{}
+
+func NewDEGIROStatementImporter() DEGIROStatementImporter {
+	return DEGIROStatementImporter{}
+}
+
+type degiroRow struct {
+	row      int
+	dateTime time.Time
+	record   []string
+}
+
+func (i DEGIROStatementImporter) Parse(reader io.Reader) (domain.Statement, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	stringRecords, err := csvReader.ReadAll()
+	if err != nil {
+		return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to read CSV records: %s", err.Error())
+	}
+	if len(stringRecords) == 0 {
+		return domain.Statement{}, errors.Wrap(ErrInvalidStatement, "CSV file is empty")
+	}
+
+	rows := make([]degiroRow, 0)
+	fxRates := make(map[string]float64)
+	for index, stringRecord := range stringRecords[1:] {
+		row := index + 2
+		if len(stringRecord) <= degiroColumnOrderID {
+			return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "expected %d columns on row %d", degiroColumnOrderID+1, row)
+		}
+		for column := range stringRecord {
+			stringRecord[column] = strings.TrimSpace(stringRecord[column])
+		}
+
+		dateTime, err := time.Parse("02-01-2006 15:04", stringRecord[degiroColumnDate]+" "+stringRecord[degiroColumnTime])
+		if err != nil {
+			return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse date on row %d: %s", row, err.Error())
+		}
+
+		if stringRecord[degiroColumnFX] != "" && stringRecord[degiroColumnOrderID] != "" {
+			fxRate, err := parseStatementNumber(stringRecord[degiroColumnFX])
+			if err != nil || fxRate == 0 {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "invalid FX on row %d", row)
+			}
+			fxRates[stringRecord[degiroColumnOrderID]] = fxRate
+		}
+
+		rows = append(rows, degiroRow{row: row, dateTime: dateTime, record: stringRecord})
+	}
+
+	slices.Reverse(rows)
+	sort.SliceStable(rows, func(a, b int) bool { return rows[a].dateTime.Before(rows[b].dateTime) })
+
+	currency := degiroAccountCurrency(rows)
+	accountCurrency, err := domain.ParseCurrency(currency)
+	if err != nil {
+		return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to find the currency of the account: %s", err.Error())
+	}
+
+	statement := domain.Statement{Currency: accountCurrency}
+	positions := newStatementPositions()
+	var cash int64
+	for _, row := range rows {
+		record := row.record
+		date := time.Date(row.dateTime.Year(), row.dateTime.Month(), row.dateTime.Day(), 0, 0, 0, 0, time.UTC)
+
+		if record[degiroColumnBalanceCurrency] == currency && record[degiroColumnBalance] != "" {
+			cash, err = parseStatementAmount(record[degiroColumnBalance])
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse balance on row %d: %s", row.row, err.Error())
+			}
+		}
+
+		description := record[degiroColumnDescription]
+		if movement := degiroCashMovement(description); movement != 0 {
+			change, err := parseStatementAmount(record[degiroColumnChange])
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse change on row %d: %s", row.row, err.Error())
+			}
+			if change < 0 {
+				change = -change
+			}
+			statement.CashMovements = append(statement.CashMovements, domain.NewStatementCashMovement(date, movement*change))
+		}
+
+		if matches := degiroTradePattern.FindStringSubmatch(description); matches != nil {
+			units, err := parseStatementNumber(matches[2])
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse units on row %d: %s", row.row, err.Error())
+			}
+			price, err := parseStatementNumber(matches[3])
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse price on row %d: %s", row.row, err.Error())
+			}
+			if matches[4] != currency {
+				fxRate, ok := fxRates[record[degiroColumnOrderID]]
+				if !ok {
+					return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "no FX for the trade in %s on row %d", matches[4], row.row)
+				}
+				price /= fxRate
+			}
+
+			side := strings.ToLower(matches[1])
+			if side == "sell" || side == "verkoop" {
+				units = -units
+			}
+			positions.trade(record[degiroColumnISIN], units, price)
+		}
+
+		statement.Valuations = append(statement.Valuations, domain.NewStatementValuation(date, cash+positions.value()))
+	}
+
+	return statement, nil
+}
+
+// degiroCashMovement tells whether the description is of a deposit (1) or a withdrawal (-1). The reservations of iDEAL
+// deposits are followed by the deposits themselves, so they are not a cash movement (0).
+func degiroCashMovement(description string) int64 {
+	description = strings.ToLower(description)
+	switch {
+	case strings.Contains(description, "reservation") || strings.Contains(description, "reservering"):
+		return 0
+	case strings.Contains(description, "withdrawal") || strings.Contains(description, "terugstorting"):
+		return -1
+	case strings.Contains(description, "deposit") || strings.Contains(description, "storting"):
+		return 1
+	}
+	return 0
+}
+
+// degiroAccountCurrency is the currency of the first deposit or, without deposits, of the first balance.
+func degiroAccountCurrency(rows []degiroRow) string {
+	for _, row := range rows {
+		if degiroCashMovement(row.record[degiroColumnDescription]) == 1 {
+			return row.record[degiroColumnChangeCurrency]
+		}
+	}
+	for _, row := range rows {
+		if row.record[degiroColumnBalanceCurrency] != "" {
+			return row.record[degiroColumnBalanceCurrency]
+		}
+	}
+	return ""
+}
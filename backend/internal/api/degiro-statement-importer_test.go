@@ -0,0 +1,69 @@
+package api
+
+import "testing"
+
+const degiroHeader = "Date,Time,Value date,Product,ISIN,Description,FX,Change,,Balance,,Order Id\n"
+
+func TestDEGIROStatementImporter_Parse(t *testing.T) {
+	runStatementTests(t, NewDEGIROStatementImporter(), []statementTest{
+		{
+			name:     "account CSV",
+			input:    fixture("degiro-account.csv"),
+			currency: "EUR",
+			// the reservation of the deposit on 2023-04-01 is left out
+			cashMovements: map[string]int64{
+				"2023-02-15": 400,
+				"2023-03-01": 100000,
+				"2023-04-01": 50000,
+				"2023-05-02": -20000,
+			},
+			// the Apple shares bought in USD on 2023-04-03 are valued at the FX of the same order
+			days: map[string]statementDay{
+				"2023-02-15": {cash: 400, value: 400},
+				"2023-03-01": {cash: 100000, value: 100400},
+				"2023-03-06": {value: 100300},
+				"2023-04-01": {cash: 50000, value: 150300},
+				"2023-04-03": {value: 150301},
+				"2023-04-17": {value: 150616},
+				"2023-05-02": {cash: -20000, value: 130616},
+			},
+		},
+		{
+			name: "reservation without deposit",
+			input: inline(degiroHeader +
+				`01-04-2023,09:58,01-04-2023,,,Reservation iDEAL / Sofort Deposit,,EUR,"500,00",EUR,"500,00",` + "\n"),
+			currency:      "EUR",
+			cashMovements: map[string]int64{},
+			days: map[string]statementDay{
+				"2023-04-01": {value: 50000},
+			},
+		},
+		{
+			name: "Dutch descriptions",
+			input: inline(degiroHeader +
+				`02-03-2023,10:00,02-03-2023,,,flatex terugstorting,,EUR,"-100,00",EUR,"0,00",` + "\n" +
+				`01-03-2023,10:00,01-03-2023,,,iDEAL storting,,EUR,"100,00",EUR,"100,00",` + "\n"),
+			currency: "EUR",
+			cashMovements: map[string]int64{
+				"2023-03-01": 10000,
+				"2023-03-02": -10000,
+			},
+			days: map[string]statementDay{
+				"2023-03-01": {cash: 10000, value: 10000},
+				"2023-03-02": {cash: -10000, value: 0},
+			},
+		},
+		{
+			name: "trade in another currency without FX",
+			input: inline(degiroHeader +
+				`03-04-2023,15:31,03-04-2023,APPLE INC. - COMMON,US0378331005,"Buy 2 Apple Inc.@177,12 USD (US0378331005)",,USD,"-354,24",USD,"-354,24",8e2b7c1a` + "\n" +
+				`01-04-2023,10:00,01-04-2023,,,iDEAL Deposit,,EUR,"500,00",EUR,"500,00",` + "\n"),
+			invalid: true,
+		},
+		{
+			name:    "invalid date",
+			input:   inline(degiroHeader + `2023-04-01,10:00,01-04-2023,,,iDEAL Deposit,,EUR,"500,00",EUR,"500,00",` + "\n"),
+			invalid: true,
+		},
+	})
+}
@@ -17,6 +17,7 @@ import (
 type DemoHandler struct {
 	userService                 services.UserService
 	investmentService           services.InvestmentService
+	portfolioService            services.PortfolioService
 	investmentUpdateCSVImporter InvestmentUpdateCSVImporter
 	tokenService                TokenService
 }
@@ -24,12 +25,14 @@ type DemoHandler struct {
 func NewDemoHandler(
 	userService services.UserService,
 	investmentService services.InvestmentService,
+	portfolioService services.PortfolioService,
 	investmentUpdateCSVImporter InvestmentUpdateCSVImporter,
 	tokenService TokenService,
 ) DemoHandler {
 	return DemoHandler{
 		userService:                 userService,
 		investmentService:           investmentService,
+		portfolioService:            portfolioService,
 		investmentUpdateCSVImporter: investmentUpdateCSVImporter,
 		tokenService:                tokenService,
 	}
@@ -49,27 +52,32 @@ func (h DemoHandler) CreateDemoSession(c *gin.Context) error {
 		return errors.Wrap(err, "failed to create user")
 	}
 
-	err = h.createNTWorldInvestment(demoUser)
+	portfolio, err := h.portfolioService.FindDefaultByUserID(demoUser.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to find default portfolio")
+	}
+
+	err = h.createNTWorldInvestment(demoUser, portfolio)
 	if err != nil {
 		return errors.Wrap(err, "failed to create NT World investment")
 	}
 
-	err = h.createNTEmergingMarketsInvestment(demoUser)
+	err = h.createNTEmergingMarketsInvestment(demoUser, portfolio)
 	if err != nil {
 		return errors.Wrap(err, "failed to create NT Emerging Markets investment")
 	}
 
-	err = h.createNTSmallCapInvestment(demoUser)
+	err = h.createNTSmallCapInvestment(demoUser, portfolio)
 	if err != nil {
 		return errors.Wrap(err, "failed to create NT Small Cap investment")
 	}
 
-	err = h.createBitcoinInvestment(demoUser)
+	err = h.createBitcoinInvestment(demoUser, portfolio)
 	if err != nil {
 		return errors.Wrap(err, "failed to create Bitcoin investment")
 	}
 
-	err = h.createCashInvestment(demoUser)
+	err = h.createCashInvestment(demoUser, portfolio)
 	if err != nil {
 		return errors.Wrap(err, "failed to create Cash investment")
 	}
@@ -84,11 +92,14 @@ func (h DemoHandler) CreateDemoSession(c *gin.Context) error {
 	return nil
 }
 
-func (h DemoHandler) createNTWorldInvestment(demoUser domain.User) error {
+func (h DemoHandler) createNTWorldInvestment(demoUser domain.User, portfolio domain.Portfolio) error {
 	investment, err := h.investmentService.Create(domain.NewCreateInvestmentCommand(
 		domain.InvestmentTypeFund,
 		"NT World",
+		"",
+		nil,
 		demoUser,
+		portfolio,
 		false,
 		nil,
 	))
@@ -102,18 +113,26 @@ func (h DemoHandler) createNTWorldInvestment(demoUser domain.User) error {
 	}
 	defer csvFile.Close()
 
-	err = h.investmentUpdateCSVImporter.Import(csv.NewReader(csvFile), investment)
+	_, _, err = h.investmentUpdateCSVImporter.Import(
+		csv.NewReader(csvFile),
+		investment,
+		domain.DefaultImportProfile(),
+		domain.MergeStrategyFail,
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to import CSV updates")
 	}
 	return nil
 }
 
-func (h DemoHandler) createNTEmergingMarketsInvestment(demoUser domain.User) error {
+func (h DemoHandler) createNTEmergingMarketsInvestment(demoUser domain.User, portfolio domain.Portfolio) error {
 	investment, err := h.investmentService.Create(domain.NewCreateInvestmentCommand(
 		domain.InvestmentTypeFund,
 		"NT Emerging Markets",
+		"",
+		nil,
 		demoUser,
+		portfolio,
 		false,
 		nil,
 	))
@@ -127,18 +146,26 @@ func (h DemoHandler) createNTEmergingMarketsInvestment(demoUser domain.User) err
 	}
 	defer csvFile.Close()
 
-	err = h.investmentUpdateCSVImporter.Import(csv.NewReader(csvFile), investment)
+	_, _, err = h.investmentUpdateCSVImporter.Import(
+		csv.NewReader(csvFile),
+		investment,
+		domain.DefaultImportProfile(),
+		domain.MergeStrategyFail,
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to import CSV updates")
 	}
 	return nil
 }
 
-func (h DemoHandler) createNTSmallCapInvestment(demoUser domain.User) error {
+func (h DemoHandler) createNTSmallCapInvestment(demoUser domain.User, portfolio domain.Portfolio) error {
 	investment, err := h.investmentService.Create(domain.NewCreateInvestmentCommand(
 		domain.InvestmentTypeFund,
 		"NT Small Cap",
+		"",
+		nil,
 		demoUser,
+		portfolio,
 		false,
 		nil,
 	))
@@ -152,18 +179,26 @@ func (h DemoHandler) createNTSmallCapInvestment(demoUser domain.User) error {
 	}
 	defer csvFile.Close()
 
-	err = h.investmentUpdateCSVImporter.Import(csv.NewReader(csvFile), investment)
+	_, _, err = h.investmentUpdateCSVImporter.Import(
+		csv.NewReader(csvFile),
+		investment,
+		domain.DefaultImportProfile(),
+		domain.MergeStrategyFail,
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to import CSV updates")
 	}
 	return nil
 }
 
-func (h DemoHandler) createBitcoinInvestment(demoUser domain.User) error {
+func (h DemoHandler) createBitcoinInvestment(demoUser domain.User, portfolio domain.Portfolio) error {
 	investment, err := h.investmentService.Create(domain.NewCreateInvestmentCommand(
 		domain.InvestmentTypeCrypto,
 		"Bitcoin",
+		"",
+		nil,
 		demoUser,
+		portfolio,
 		false,
 		nil,
 	))
@@ -177,18 +212,26 @@ func (h DemoHandler) createBitcoinInvestment(demoUser domain.User) error {
 	}
 	defer csvFile.Close()
 
-	err = h.investmentUpdateCSVImporter.Import(csv.NewReader(csvFile), investment)
+	_, _, err = h.investmentUpdateCSVImporter.Import(
+		csv.NewReader(csvFile),
+		investment,
+		domain.DefaultImportProfile(),
+		domain.MergeStrategyFail,
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to import CSV updates")
 	}
 	return nil
 }
 
-func (h DemoHandler) createCashInvestment(demoUser domain.User) error {
+func (h DemoHandler) createCashInvestment(demoUser domain.User, portfolio domain.Portfolio) error {
 	_, err := h.investmentService.Create(domain.NewCreateInvestmentCommand(
 		domain.InvestmentTypeCash,
 		"Cash",
+		"",
+		nil,
 		demoUser,
+		portfolio,
 		false,
 		pointer.Of(domain.NewInitialInvestmentUpdate(
 			pointer.Of(time.Date(2021, 1, 1, 0, 0, 0, 0, time.Local)),
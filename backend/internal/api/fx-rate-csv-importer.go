@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/csv"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidFXRateCSV = errors.New("invalid fx rate CSV")
+
+// FXRateCSVImporter imports rates in the format of the ECB reference rates: a "Date" column followed by one column
+// per currency with the amount of that currency per euro. Missing rates are empty or "N/A".
+type FXRateCSVImporter struct {
+	fxRateService services.FXRateService
+}
+
+func NewFXRateCSVImporter(fxRateService services.FXRateService) FXRateCSVImporter {
+	return FXRateCSVImporter{
+		fxRateService: fxRateService,
+	}
+}
+
+func (s FXRateCSVImporter) Import(csvReader *csv.Reader) (int, error) {
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	stringRecords, err := csvReader.ReadAll()
+	if err != nil {
+		return 0, errors.Wrapf(ErrInvalidFXRateCSV, "failed to read CSV records: %s", err.Error())
+	}
+	if len(stringRecords) == 0 {
+		return 0, errors.Wrap(ErrInvalidFXRateCSV, "CSV file is empty")
+	}
+
+	header := stringRecords[0]
+	currencies := make([]*domain.Currency, len(header))
+	for i := 1; i < len(header); i++ {
+		if strings.TrimSpace(header[i]) == "" {
+			continue // the ECB files end every line with a comma
+		}
+		currency, err := domain.ParseCurrency(strings.TrimSpace(header[i]))
+		if err != nil {
+			return 0, errors.Wrapf(ErrInvalidFXRateCSV, "failed to parse currency in column %d: %s", i+1, err.Error())
+		}
+		currencies[i] = &currency
+	}
+
+	rates := make([]domain.FXRate, 0)
+	for i := 1; i < len(stringRecords); i++ {
+		stringRecord := stringRecords[i]
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(stringRecord[0]))
+		if err != nil {
+			return 0, errors.Wrapf(ErrInvalidFXRateCSV, "failed to parse date on row %d: %s", i+1, err.Error())
+		}
+
+		for j := 1; j < len(stringRecord) && j < len(currencies); j++ {
+			value := strings.TrimSpace(stringRecord[j])
+			if currencies[j] == nil || value == "" || value == "N/A" {
+				continue
+			}
+
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, errors.Wrapf(ErrInvalidFXRateCSV, "failed to parse %s rate on row %d: %s", *currencies[j], i+1, err.Error())
+			}
+			if rate <= 0 {
+				return 0, errors.Wrapf(ErrInvalidFXRateCSV, "%s rate on row %d is not positive", *currencies[j], i+1)
+			}
+			rates = append(rates, domain.NewFXRate(date, *currencies[j], rate))
+		}
+	}
+
+	err = s.fxRateService.Save(rates)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to save fx rates")
+	}
+
+	return len(rates), nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+type FXRateHandler struct {
+	fxRateCSVImporter FXRateCSVImporter
+}
+
+func NewFXRateHandler(fxRateCSVImporter FXRateCSVImporter) FXRateHandler {
+	return FXRateHandler{
+		fxRateCSVImporter: fxRateCSVImporter,
+	}
+}
+
+func (h FXRateHandler) ImportFXRates(c *gin.Context) (response[importFXRatesDto], error) {
+	csvFormFile, err := c.FormFile("csvFile")
+	if err != nil {
+		return response[importFXRatesDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	csvFile, err := csvFormFile.Open()
+	if err != nil {
+		return response[importFXRatesDto]{}, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer csvFile.Close()
+
+	imported, err := h.fxRateCSVImporter.Import(csv.NewReader(csvFile))
+	if err != nil {
+		if errors.Is(err, ErrInvalidFXRateCSV) {
+			return response[importFXRatesDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[importFXRatesDto]{}, errors.Wrap(err, "failed to import CSV fx rates")
+	}
+
+	return newResponse(http.StatusOK, importFXRatesDto{Imported: imported}), nil
+}
+
+type importFXRatesDto struct {
+	Imported int `json:"imported"`
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/pointer"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type GoalHandler struct {
+	goalService services.GoalService
+}
+
+func NewGoalHandler(goalService services.GoalService) GoalHandler {
+	return GoalHandler{
+		goalService: goalService,
+	}
+}
+
+func (h GoalHandler) GetGoals(c *gin.Context) (response[[]goalDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	goals, err := h.goalService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[[]goalDto]{}, fmt.Errorf("failed to find goals: %w", err)
+	}
+
+	dtos := make([]goalDto, 0)
+	for _, goal := range goals {
+		dto, err := h.findProgress(goal)
+		if err != nil {
+			return response[[]goalDto]{}, err
+		}
+		dtos = append(dtos, dto)
+	}
+
+	return newResponse(http.StatusOK, dtos), nil
+}
+
+func (h GoalHandler) GetGoal(c *gin.Context) (response[goalDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	goal, err := h.findOwnedGoal(c.Param("id"), tokenUserID, "not allowed to read goal")
+	if err != nil {
+		return response[goalDto]{}, err
+	}
+
+	dto, err := h.findProgress(goal)
+	if err != nil {
+		return response[goalDto]{}, err
+	}
+
+	return newResponse(http.StatusOK, dto), nil
+}
+
+func (h GoalHandler) CreateGoal(c *gin.Context) (response[goalDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveGoalRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[goalDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	command, err := request.toCommand()
+	if err != nil {
+		return response[goalDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	goal, err := h.goalService.Create(tokenUserID, command)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidGoal) {
+			return response[goalDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[goalDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		return response[goalDto]{}, fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	dto, err := h.findProgress(goal)
+	if err != nil {
+		return response[goalDto]{}, err
+	}
+
+	return newResponse(http.StatusCreated, dto), nil
+}
+
+func (h GoalHandler) UpdateGoal(c *gin.Context) (response[goalDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveGoalRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[goalDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	command, err := request.toCommand()
+	if err != nil {
+		return response[goalDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	goal, err := h.findOwnedGoal(c.Param("id"), tokenUserID, "not allowed to update goal")
+	if err != nil {
+		return response[goalDto]{}, err
+	}
+
+	updated, err := h.goalService.Update(goal, command)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidGoal) {
+			return response[goalDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[goalDto]{}, fmt.Errorf("failed to update goal: %w", err)
+	}
+
+	dto, err := h.findProgress(updated)
+	if err != nil {
+		return response[goalDto]{}, err
+	}
+
+	return newResponse(http.StatusOK, dto), nil
+}
+
+func (h GoalHandler) DeleteGoal(c *gin.Context) (response[empty], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	goal, err := h.findOwnedGoal(c.Param("id"), tokenUserID, "not allowed to delete goal")
+	if err != nil {
+		return response[empty]{}, err
+	}
+
+	err = h.goalService.DeleteByID(goal.ID)
+	if err != nil {
+		return response[empty]{}, fmt.Errorf("failed to delete goal: %w", err)
+	}
+
+	return newEmptyResponse(http.StatusNoContent), nil
+}
+
+func (h GoalHandler) findOwnedGoal(id, userID, forbiddenMessage string) (domain.Goal, error) {
+	goal, err := h.goalService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrGoalNotFound {
+			return domain.Goal{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.Goal{}, fmt.Errorf("failed to find goal: %w", err)
+	}
+
+	if goal.UserID != userID {
+		return domain.Goal{}, NewError(http.StatusForbidden, forbiddenMessage)
+	}
+
+	return goal, nil
+}
+
+func (h GoalHandler) findProgress(goal domain.Goal) (goalDto, error) {
+	progress, err := h.goalService.FindProgress(goal)
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return goalDto{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		return goalDto{}, fmt.Errorf("failed to find goal progress: %w", err)
+	}
+
+	return toGoalDto(progress), nil
+}
+
+type saveGoalRequest struct {
+	Name                     string   `json:"name"`
+	TargetAmount             int64    `json:"targetAmount"`
+	TargetDate               string   `json:"targetDate"`
+	Currency                 *string  `json:"currency"`
+	ExpectedReturnPercentage float64  `json:"expectedReturnPercentage"`
+	InvestmentIDs            []string `json:"investmentIds"`
+	TagIDs                   []string `json:"tagIds"`
+}
+
+func (r saveGoalRequest) toCommand() (domain.SaveGoalCommand, error) {
+	targetDate, err := time.Parse("2006-01-02", r.TargetDate)
+	if err != nil {
+		return domain.SaveGoalCommand{}, fmt.Errorf("failed to parse targetDate: %w", err)
+	}
+
+	var currency domain.Currency
+	if r.Currency != nil {
+		currency, err = domain.ParseCurrency(*r.Currency)
+		if err != nil {
+			return domain.SaveGoalCommand{}, err
+		}
+	}
+
+	return domain.NewSaveGoalCommand(
+		r.Name,
+		r.TargetAmount,
+		targetDate,
+		currency,
+		r.ExpectedReturnPercentage,
+		r.InvestmentIDs,
+		r.TagIDs,
+	), nil
+}
+
+func toGoalDto(p domain.GoalProgress) goalDto {
+	var projectedCompletionDate *string
+	if p.ProjectedCompletionDate != nil {
+		projectedCompletionDate = pointer.Of(p.ProjectedCompletionDate.Format("2006-01-02"))
+	}
+
+	return newGoalDto(
+		p.Goal.ID,
+		p.Goal.Name,
+		p.Goal.TargetAmount,
+		p.Goal.TargetDate.Format("2006-01-02"),
+		p.Goal.Currency,
+		p.Goal.ExpectedReturnPercentage,
+		p.Goal.InvestmentIDs,
+		p.Goal.TagIDs,
+		newGoalProgressDto(
+			p.CurrentValue,
+			p.Percentage,
+			p.MonthlyContribution,
+			p.RequiredMonthlyContribution,
+			projectedCompletionDate,
+			p.Status,
+		),
+	)
+}
+
+type goalDto struct {
+	ID                       string          `json:"id"`
+	Name                     string          `json:"name"`
+	TargetAmount             int64           `json:"targetAmount"`
+	TargetDate               string          `json:"targetDate"`
+	Currency                 domain.Currency `json:"currency"`
+	ExpectedReturnPercentage float64         `json:"expectedReturnPercentage"`
+	InvestmentIDs            []string        `json:"investmentIds"`
+	TagIDs                   []string        `json:"tagIds"`
+	Progress                 goalProgressDto `json:"progress"`
+}
+
+func newGoalDto(
+	id,
+	name string,
+	targetAmount int64,
+	targetDate string,
+	currency domain.Currency,
+	expectedReturnPercentage float64,
+	investmentIDs,
+	tagIDs []string,
+	progress goalProgressDto,
+) goalDto {
+	return goalDto{
+		ID:                       id,
+		Name:                     name,
+		TargetAmount:             targetAmount,
+		TargetDate:               targetDate,
+		Currency:                 currency,
+		ExpectedReturnPercentage: expectedReturnPercentage,
+		InvestmentIDs:            investmentIDs,
+		TagIDs:                   tagIDs,
+		Progress:                 progress,
+	}
+}
+
+type goalProgressDto struct {
+	CurrentValue                int64             `json:"currentValue"`
+	Percentage                  float64           `json:"percentage"`
+	MonthlyContribution         int64             `json:"monthlyContribution"`
+	RequiredMonthlyContribution int64             `json:"requiredMonthlyContribution"`
+	ProjectedCompletionDate     *string           `json:"projectedCompletionDate"`
+	Status                      domain.GoalStatus `json:"status"`
+}
+
+func newGoalProgressDto(
+	currentValue int64,
+	percentage float64,
+	monthlyContribution,
+	requiredMonthlyContribution int64,
+	projectedCompletionDate *string,
+	status domain.GoalStatus,
+) goalProgressDto {
+	return goalProgressDto{
+		CurrentValue:                currentValue,
+		Percentage:                  percentage,
+		MonthlyContribution:         monthlyContribution,
+		RequiredMonthlyContribution: requiredMonthlyContribution,
+		ProjectedCompletionDate:     projectedCompletionDate,
+		Status:                      status,
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/xml"
+	"growfolio/internal/domain"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ibkrDepositsWithdrawals are the names of the type of deposits and withdrawals in the cash transactions, which differ
+// between the versions of the Flex Query.
+var ibkrDepositsWithdrawals = []string{"Deposits/Withdrawals", "Deposits & Withdrawals"}
+
+// IBKRFlexStatementImporter reads the XML of an Interactive Brokers Flex Query with the sections "Cash Transactions" and
+// "Net Asset Value (NAV) in Base". The statement is in the base currency of the account, so the deposits and
+// withdrawals are converted at their rate to the base currency, and the valuations are the totals of the NAV.
+type IBKRFlexStatementImporter struct{}
+
+func NewIBKRFlexStatementImporter() IBKRFlexStatementImporter {
+	return IBKRFlexStatementImporter{}
+}
+
+type ibkrFlexQueryResponse struct {
+	Statements []ibkrFlexStatement `xml:"FlexStatements>FlexStatement"`
+}
+
+type ibkrFlexStatement struct {
+	AccountInformation ibkrAccountInformation `xml:"AccountInformation"`
+	CashTransactions   []ibkrCashTransaction  `xml:"CashTransactions>CashTransaction"`
+	EquitySummaries    []ibkrEquitySummary    `xml:"EquitySummaryInBase>EquitySummaryByReportDateInBase"`
+}
+
+type ibkrCashTransaction struct {
+	Type          string `xml:"type,attr"`
+	Amount        string `xml:"amount,attr"`
+	FXRateToBase  string `xml:"fxRateToBase,attr"`
+	DateTime      string `xml:"dateTime,attr"`
+	ReportDate    string `xml:"reportDate,attr"`
+	LevelOfDetail string `xml:"levelOfDetail,attr"`
+}
+
+type ibkrAccountInformation struct {
+	Currency string `xml:"currency,attr"`
+}
+
+type ibkrEquitySummary struct {
+	Currency   string `xml:"currency,attr"`
+	ReportDate string `xml:"reportDate,attr"`
+	Total      string `xml:"total,attr"`
+}
+
+func (i IBKRFlexStatementImporter) Parse(reader io.Reader) (domain.Statement, error) {
+	var response ibkrFlexQueryResponse
+	err := xml.NewDecoder(reader).Decode(&response)
+	if err != nil {
+		return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to decode XML: %s", err.Error())
+	}
+	if len(response.Statements) == 0 {
+		return domain.Statement{}, errors.Wrap(ErrInvalidStatement, "no FlexStatement found")
+	}
+
+	currency, err := domain.ParseCurrency(ibkrBaseCurrency(response.Statements))
+	if err != nil {
+		return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to find the base currency: %s", err.Error())
+	}
+
+	statement := domain.Statement{Currency: currency}
+	for _, flexStatement := range response.Statements {
+		for _, transaction := range flexStatement.CashTransactions {
+			if !ibkrIsDepositOrWithdrawal(transaction.Type) || strings.EqualFold(transaction.LevelOfDetail, "SUMMARY") {
+				continue
+			}
+
+			dateText := transaction.DateTime
+			if dateText == "" {
+				dateText = transaction.ReportDate
+			}
+			date, err := parseIBKRDate(dateText)
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse date of cash transaction: %s", err.Error())
+			}
+
+			amount, err := parseStatementNumber(transaction.Amount)
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse amount of cash transaction: %s", err.Error())
+			}
+			if transaction.FXRateToBase != "" {
+				fxRate, err := parseStatementNumber(transaction.FXRateToBase)
+				if err != nil {
+					return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse fxRateToBase of cash transaction: %s", err.Error())
+				}
+				amount *= fxRate
+			}
+
+			cents := int64(math.Round(amount * 100))
+			statement.CashMovements = append(statement.CashMovements, domain.NewStatementCashMovement(date, cents))
+		}
+
+		for _, summary := range flexStatement.EquitySummaries {
+			date, err := parseIBKRDate(summary.ReportDate)
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse reportDate of NAV: %s", err.Error())
+			}
+			total, err := parseStatementAmount(summary.Total)
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse total of NAV: %s", err.Error())
+			}
+			statement.Valuations = append(statement.Valuations, domain.NewStatementValuation(date, total))
+		}
+	}
+
+	return statement, nil
+}
+
+// ibkrBaseCurrency is the currency of the section "Account Information" or, when the query leaves it out, of the NAV.
+func ibkrBaseCurrency(statements []ibkrFlexStatement) string {
+	for _, flexStatement := range statements {
+		if flexStatement.AccountInformation.Currency != "" {
+			return flexStatement.AccountInformation.Currency
+		}
+	}
+	for _, flexStatement := range statements {
+		for _, summary := range flexStatement.EquitySummaries {
+			if summary.Currency != "" {
+				return summary.Currency
+			}
+		}
+	}
+	return ""
+}
+
+func ibkrIsDepositOrWithdrawal(transactionType string) bool {
+	for _, t := range ibkrDepositsWithdrawals {
+		if strings.EqualFold(transactionType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIBKRDate reads the date of the formats yyyyMMdd and yyyy-MM-dd that a Flex Query can be set to, leaving out the
+// time that follows the date.
+func parseIBKRDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 10 && s[4] == '-' {
+		return time.Parse("2006-01-02", s[:10])
+	}
+	if len(s) >= 8 {
+		return time.Parse("20060102", s[:8])
+	}
+	return time.Time{}, errors.Errorf("unknown date %q", s)
+}
@@ -0,0 +1,62 @@
+package api
+
+import "testing"
+
+func TestIBKRFlexStatementImporter_Parse(t *testing.T) {
+	runStatementTests(t, NewIBKRFlexStatementImporter(), []statementTest{
+		{
+			name:     "Flex Query",
+			input:    fixture("ibkr-flex.xml"),
+			currency: "EUR",
+			// the deposit of USD 489.31 is converted at its fxRateToBase, the dividend and the SUMMARY row are left out
+			cashMovements: map[string]int64{
+				"2023-03-02": 200000,
+				"2023-03-07": 46042,
+				"2023-03-09": -30000,
+			},
+			days: map[string]statementDay{
+				"2023-03-01": {value: 0},
+				"2023-03-02": {cash: 200000, value: 200000},
+				"2023-03-03": {value: 200755},
+				"2023-03-06": {value: 202375},
+				"2023-03-07": {cash: 46042, value: 247607},
+				"2023-03-08": {value: 247172},
+				"2023-03-09": {cash: -30000, value: 219392},
+				"2023-03-10": {value: 220327},
+			},
+		},
+		{
+			name: "base currency of the account information and dates with dashes",
+			input: inline(`<FlexQueryResponse><FlexStatements><FlexStatement>
+<AccountInformation currency="USD" />
+<CashTransactions>
+<CashTransaction currency="EUR" fxRateToBase="1.1" amount="100" type="Deposits &amp; Withdrawals" dateTime="2023-03-02;101500" levelOfDetail="DETAIL" />
+<CashTransaction currency="BASE_SUMMARY" fxRateToBase="1" amount="110" type="Deposits &amp; Withdrawals" reportDate="2023-03-02" levelOfDetail="SUMMARY" />
+</CashTransactions>
+</FlexStatement></FlexStatements></FlexQueryResponse>`),
+			currency: "USD",
+			cashMovements: map[string]int64{
+				"2023-03-02": 11000,
+			},
+		},
+		{
+			name: "no base currency",
+			input: inline(`<FlexQueryResponse><FlexStatements><FlexStatement>
+<CashTransactions>
+<CashTransaction currency="EUR" fxRateToBase="1" amount="100" type="Deposits/Withdrawals" dateTime="20230302" levelOfDetail="DETAIL" />
+</CashTransactions>
+</FlexStatement></FlexStatements></FlexQueryResponse>`),
+			invalid: true,
+		},
+		{
+			name:    "no FlexStatement",
+			input:   inline(`<FlexQueryResponse><FlexStatements></FlexStatements></FlexQueryResponse>`),
+			invalid: true,
+		},
+		{
+			name:    "not XML",
+			input:   inline("Date,Amount\n"),
+			invalid: true,
+		},
+	})
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/slices"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type ImportProfileHandler struct {
+	importProfileService services.ImportProfileService
+}
+
+func NewImportProfileHandler(importProfileService services.ImportProfileService) ImportProfileHandler {
+	return ImportProfileHandler{
+		importProfileService: importProfileService,
+	}
+}
+
+func (h ImportProfileHandler) GetImportProfiles(c *gin.Context) (response[[]importProfileDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	profiles, err := h.importProfileService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[[]importProfileDto]{}, fmt.Errorf("failed to find import profiles: %w", err)
+	}
+
+	return newResponse(http.StatusOK, slices.Map(profiles, toImportProfileDto)), nil
+}
+
+func (h ImportProfileHandler) CreateImportProfile(c *gin.Context) (response[importProfileDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveImportProfileRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[importProfileDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	profile, err := h.importProfileService.Create(tokenUserID, request.toCommand())
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidImportProfile) {
+			return response[importProfileDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[importProfileDto]{}, fmt.Errorf("failed to create import profile: %w", err)
+	}
+
+	return newResponse(http.StatusCreated, toImportProfileDto(profile)), nil
+}
+
+func (h ImportProfileHandler) UpdateImportProfile(c *gin.Context) (response[importProfileDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveImportProfileRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[importProfileDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	profile, err := findOwnedImportProfile(h.importProfileService, c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[importProfileDto]{}, err
+	}
+
+	updated, err := h.importProfileService.Update(profile, request.toCommand())
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidImportProfile) {
+			return response[importProfileDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[importProfileDto]{}, fmt.Errorf("failed to update import profile: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toImportProfileDto(updated)), nil
+}
+
+func (h ImportProfileHandler) DeleteImportProfile(c *gin.Context) (response[empty], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	profile, err := findOwnedImportProfile(h.importProfileService, c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[empty]{}, err
+	}
+
+	err = h.importProfileService.DeleteByID(profile.ID)
+	if err != nil {
+		return response[empty]{}, fmt.Errorf("failed to delete import profile: %w", err)
+	}
+
+	return newEmptyResponse(http.StatusNoContent), nil
+}
+
+// findOwnedImportProfile is shared with the CSV import of the investment handler, which reads files with a saved
+// profile.
+func findOwnedImportProfile(
+	importProfileService services.ImportProfileService,
+	id,
+	userID string,
+) (domain.ImportProfile, error) {
+	profile, err := importProfileService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrImportProfileNotFound {
+			return domain.ImportProfile{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.ImportProfile{}, fmt.Errorf("failed to find import profile: %w", err)
+	}
+
+	if profile.UserID != userID {
+		return domain.ImportProfile{}, NewError(http.StatusForbidden, "not allowed to use import profile")
+	}
+
+	return profile, nil
+}
+
+// saveImportProfileRequest takes the settings of the default profile for the fields that are left out, except for the
+// thousands separator, which is none when left out.
+type saveImportProfileRequest struct {
+	Name               string            `json:"name"`
+	Delimiter          string            `json:"delimiter"`
+	DateFormat         string            `json:"dateFormat"`
+	DecimalSeparator   string            `json:"decimalSeparator"`
+	ThousandsSeparator string            `json:"thousandsSeparator"`
+	ColumnMapping      map[string]string `json:"columnMapping"`
+	AmountScale        string            `json:"amountScale"`
+}
+
+func (r saveImportProfileRequest) toCommand() domain.SaveImportProfileCommand {
+	defaultProfile := domain.DefaultImportProfile()
+
+	delimiter := r.Delimiter
+	if delimiter == "" {
+		delimiter = defaultProfile.Delimiter
+	}
+	dateFormat := r.DateFormat
+	if dateFormat == "" {
+		dateFormat = defaultProfile.DateFormat
+	}
+	decimalSeparator := r.DecimalSeparator
+	if decimalSeparator == "" {
+		decimalSeparator = defaultProfile.DecimalSeparator
+	}
+	amountScale := domain.AmountScale(r.AmountScale)
+	if amountScale == "" {
+		amountScale = defaultProfile.AmountScale
+	}
+	columnMapping := r.ColumnMapping
+	if columnMapping == nil {
+		columnMapping = map[string]string{}
+	}
+
+	return domain.NewSaveImportProfileCommand(
+		r.Name,
+		delimiter,
+		dateFormat,
+		decimalSeparator,
+		r.ThousandsSeparator,
+		columnMapping,
+		amountScale,
+	)
+}
+
+func toImportProfileDto(p domain.ImportProfile) importProfileDto {
+	return importProfileDto{
+		ID:                 p.ID,
+		Name:               p.Name,
+		Delimiter:          p.Delimiter,
+		DateFormat:         p.DateFormat,
+		DecimalSeparator:   p.DecimalSeparator,
+		ThousandsSeparator: p.ThousandsSeparator,
+		ColumnMapping:      p.ColumnMapping,
+		AmountScale:        string(p.AmountScale),
+	}
+}
+
+type importProfileDto struct {
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	Delimiter          string            `json:"delimiter"`
+	DateFormat         string            `json:"dateFormat"`
+	DecimalSeparator   string            `json:"decimalSeparator"`
+	ThousandsSeparator string            `json:"thousandsSeparator"`
+	ColumnMapping      map[string]string `json:"columnMapping"`
+	AmountScale        string            `json:"amountScale"`
+}
@@ -6,6 +6,8 @@ import (
 	"growfolio/internal/domain"
 	"growfolio/internal/domain/services"
 	"growfolio/internal/pointer"
+	"growfolio/internal/slices"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -19,21 +21,39 @@ import (
 type InvestmentHandler struct {
 	investmentService          services.InvestmentService
 	investmentUpdateService    services.InvestmentUpdateService
+	transactionService         services.TransactionService
+	holdingService             services.HoldingService
+	portfolioService           services.PortfolioService
+	settingsService            services.SettingsService
+	importProfileService       services.ImportProfileService
 	userRepository             services.UserRepository
 	investmentUpdateCSVService InvestmentUpdateCSVImporter
+	statementImporter          InvestmentUpdateStatementImporter
 }
 
 func NewInvestmentHandler(
 	investmentService services.InvestmentService,
 	investmentUpdateService services.InvestmentUpdateService,
+	transactionService services.TransactionService,
+	holdingService services.HoldingService,
+	portfolioService services.PortfolioService,
+	settingsService services.SettingsService,
+	importProfileService services.ImportProfileService,
 	userRepository services.UserRepository,
 	investmentUpdateCSVService InvestmentUpdateCSVImporter,
+	statementImporter InvestmentUpdateStatementImporter,
 ) InvestmentHandler {
 	return InvestmentHandler{
 		investmentService:          investmentService,
 		investmentUpdateService:    investmentUpdateService,
+		transactionService:         transactionService,
+		holdingService:             holdingService,
+		portfolioService:           portfolioService,
+		settingsService:            settingsService,
+		importProfileService:       importProfileService,
 		userRepository:             userRepository,
 		investmentUpdateCSVService: investmentUpdateCSVService,
+		statementImporter:          statementImporter,
 	}
 }
 
@@ -41,14 +61,33 @@ func (h InvestmentHandler) GetInvestments(c *gin.Context) (response[[]investment
 	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
 	tokenUserID := tokenClaims["userId"].(string)
 
-	investments, err := h.investmentService.FindByUserID(tokenUserID)
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]investmentDto]{}, err
+	}
+
+	var investments []domain.Investment
+	if c.Query("includeClosed") == "true" {
+		investments, err = h.investmentService.FindByPortfolioID(portfolio.ID)
+	} else {
+		investments, err = h.investmentService.FindActiveByPortfolioID(portfolio.ID)
+	}
 	if err != nil {
 		return response[[]investmentDto]{}, errors.Wrap(err, "failed to find investments")
 	}
 
+	if tagIDFilter := c.Query("tagId"); tagIDFilter != "" {
+		investments = slices.Filter(investments, func(i domain.Investment) bool { return i.HasTag(tagIDFilter) })
+	}
+
+	settings, err := h.settingsService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[[]investmentDto]{}, errors.Wrap(err, "failed to find settings")
+	}
+
 	dtos := make([]investmentDto, 0)
 	for _, investment := range investments {
-		dtos = append(dtos, toInvestmentDto(investment))
+		dtos = append(dtos, toInvestmentDto(investment, settings.StaleAfterDays))
 	}
 
 	return newResponse(http.StatusOK, dtos), nil
@@ -71,7 +110,12 @@ func (h InvestmentHandler) GetInvestment(c *gin.Context) (response[investmentDto
 		return response[investmentDto]{}, NewError(http.StatusForbidden, "not allowed to read investment")
 	}
 
-	return newResponse(http.StatusOK, toInvestmentDto(investment)), nil
+	settings, err := h.settingsService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[investmentDto]{}, errors.Wrap(err, "failed to find settings")
+	}
+
+	return newResponse(http.StatusOK, toInvestmentDto(investment, settings.StaleAfterDays)), nil
 }
 
 func (h InvestmentHandler) DeleteInvestment(c *gin.Context) (response[empty], error) {
@@ -89,11 +133,6 @@ func (h InvestmentHandler) DeleteInvestment(c *gin.Context) (response[empty], er
 		return response[empty]{}, NewError(http.StatusForbidden, "not allowed to delete investment")
 	}
 
-	err = h.investmentUpdateService.DeleteByInvestmentID(investment.ID)
-	if err != nil {
-		return response[empty]{}, fmt.Errorf("failed to delete updates: %w", err)
-	}
-
 	err = h.investmentService.DeleteByID(investment.ID)
 	if err != nil {
 		return response[empty]{}, fmt.Errorf("failed to delete investment: %w", err)
@@ -120,7 +159,12 @@ func (h InvestmentHandler) CreateInvestment(c *gin.Context) (response[investment
 		return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
 	}
 
-	command, err := request.toCommand(user)
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[investmentDto]{}, err
+	}
+
+	command, err := request.toCommand(user, portfolio)
 	if err != nil {
 		return response[investmentDto]{}, fmt.Errorf("failed to map request to command: %w", err)
 	}
@@ -130,7 +174,150 @@ func (h InvestmentHandler) CreateInvestment(c *gin.Context) (response[investment
 		return response[investmentDto]{}, fmt.Errorf("failed to create investment: %w", err)
 	}
 
-	return newResponse(http.StatusCreated, toInvestmentDto(created)), nil
+	settings, err := h.settingsService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[investmentDto]{}, errors.Wrap(err, "failed to find settings")
+	}
+
+	return newResponse(http.StatusCreated, toInvestmentDto(created, settings.StaleAfterDays)), nil
+}
+
+func (h InvestmentHandler) PatchInvestment(c *gin.Context) (response[investmentDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request patchInvestmentRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[investmentDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[investmentDto]{}, NewError(http.StatusForbidden, "not allowed to update investment")
+	}
+
+	updated, err := h.investmentService.Update(investment, request.toCommand())
+	if err != nil {
+		if err == domain.ErrInvestmentIsLocked {
+			return response[investmentDto]{}, NewError(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvalidInvestment) {
+			return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[investmentDto]{}, fmt.Errorf("failed to update investment: %w", err)
+	}
+
+	settings, err := h.settingsService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[investmentDto]{}, errors.Wrap(err, "failed to find settings")
+	}
+
+	return newResponse(http.StatusOK, toInvestmentDto(updated, settings.StaleAfterDays)), nil
+}
+
+func (h InvestmentHandler) CloseInvestment(c *gin.Context) (response[investmentDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	// the body is optional
+	var request closeInvestmentRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if request.Date != nil {
+		date, err = time.Parse("2006-01-02", *request.Date)
+		if err != nil {
+			return response[investmentDto]{}, NewError(http.StatusBadRequest, "failed to parse date: "+err.Error())
+		}
+	}
+	if request.FinalValue != nil && *request.FinalValue < 0 {
+		return response[investmentDto]{}, NewError(http.StatusBadRequest, "field 'finalValue' cannot be negative")
+	}
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[investmentDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[investmentDto]{}, NewError(http.StatusForbidden, "not allowed to close investment")
+	}
+
+	closed, err := h.investmentService.Close(investment, date, request.FinalValue)
+	if err != nil {
+		if err == domain.ErrInvestmentIsLocked {
+			return response[investmentDto]{}, NewError(http.StatusForbidden, err.Error())
+		}
+		if err == domain.ErrInvestmentIsClosed {
+			return response[investmentDto]{}, NewError(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvalidInvestment) {
+			return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[investmentDto]{}, fmt.Errorf("failed to close investment: %w", err)
+	}
+
+	settings, err := h.settingsService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[investmentDto]{}, errors.Wrap(err, "failed to find settings")
+	}
+
+	return newResponse(http.StatusOK, toInvestmentDto(closed, settings.StaleAfterDays)), nil
+}
+
+func (h InvestmentHandler) ReopenInvestment(c *gin.Context) (response[investmentDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	user, err := h.userRepository.FindByID(tokenUserID)
+	if err != nil {
+		return response[investmentDto]{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[investmentDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[investmentDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[investmentDto]{}, NewError(http.StatusForbidden, "not allowed to reopen investment")
+	}
+
+	reopened, err := h.investmentService.Reopen(investment, user)
+	if err != nil {
+		if err == domain.ErrMaxInvestmentsReached {
+			return response[investmentDto]{}, NewError(http.StatusForbidden, err.Error())
+		}
+		return response[investmentDto]{}, fmt.Errorf("failed to reopen investment: %w", err)
+	}
+
+	settings, err := h.settingsService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[investmentDto]{}, errors.Wrap(err, "failed to find settings")
+	}
+
+	return newResponse(http.StatusOK, toInvestmentDto(reopened, settings.StaleAfterDays)), nil
 }
 
 func (h InvestmentHandler) CreateUpdate(c *gin.Context) (response[investmentUpdateDto], error) {
@@ -161,15 +348,35 @@ func (h InvestmentHandler) CreateUpdate(c *gin.Context) (response[investmentUpda
 		return response[investmentUpdateDto]{}, NewError(http.StatusBadRequest, err.Error())
 	}
 
+	if request.FromTransactions {
+		command.Deposit, command.Withdrawal, command.Income, err = h.transactionService.FindCashFlows(investment.ID, command.Date)
+		if err != nil {
+			return response[investmentUpdateDto]{}, fmt.Errorf("failed to find cash flows: %w", err)
+		}
+	}
+
 	update, err := h.investmentUpdateService.Create(command)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvestmentUpdateExists) {
+			return response[investmentUpdateDto]{}, NewError(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentIsLocked) {
+			return response[investmentUpdateDto]{}, NewError(http.StatusForbidden, domain.ErrInvestmentIsLocked.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentIsClosed) {
+			return response[investmentUpdateDto]{}, NewError(http.StatusConflict, domain.ErrInvestmentIsClosed.Error())
+		}
 		return response[investmentUpdateDto]{}, fmt.Errorf("failed to create investment update: %w", err)
 	}
 
 	return newResponse(http.StatusCreated, toInvestmentUpdateDto(update)), nil
 }
 
-func (h InvestmentHandler) ImportUpdates(c *gin.Context) (response[empty], error) {
+// ImportUpdates only validates the file when "dryRun" is true. Otherwise, the updates are only imported when every row
+// is valid, and the report comes back with a 422 when it is not. The "mergeStrategy" decides what happens to rows on
+// a date that already has an update and defaults to failing the import. The file is read with the saved profile of
+// "importProfileId" and otherwise with the default profile.
+func (h InvestmentHandler) ImportUpdates(c *gin.Context) (response[investmentUpdateCSVReportDto], error) {
 	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
 	tokenUserID := tokenClaims["userId"].(string)
 
@@ -177,32 +384,135 @@ func (h InvestmentHandler) ImportUpdates(c *gin.Context) (response[empty], error
 	investment, err := h.investmentService.FindByID(id)
 	if err != nil {
 		if err == domain.ErrInvestmentNotFound {
-			return response[empty]{}, NewError(http.StatusBadRequest, err.Error())
+			return response[investmentUpdateCSVReportDto]{}, NewError(http.StatusBadRequest, err.Error())
 		}
-		return response[empty]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+		return response[investmentUpdateCSVReportDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
 	}
 
 	if investment.UserID != tokenUserID {
-		return response[empty]{}, NewError(http.StatusForbidden, "not allowed to read investment")
+		return response[investmentUpdateCSVReportDto]{}, NewError(http.StatusForbidden, "not allowed to read investment")
+	}
+
+	strategy := domain.MergeStrategyFail
+	if c.Query("mergeStrategy") != "" {
+		strategy = domain.MergeStrategy(c.Query("mergeStrategy"))
+		if !strategy.IsValid() {
+			return response[investmentUpdateCSVReportDto]{}, NewError(http.StatusBadRequest, "invalid merge strategy: "+string(strategy))
+		}
+	}
+
+	profile := domain.DefaultImportProfile()
+	if c.Query("importProfileId") != "" {
+		profile, err = findOwnedImportProfile(h.importProfileService, c.Query("importProfileId"), tokenUserID)
+		if err != nil {
+			return response[investmentUpdateCSVReportDto]{}, err
+		}
 	}
 
 	csvFormFile, err := c.FormFile("csvFile")
 	if err != nil {
-		return response[empty]{}, NewError(http.StatusBadRequest, err.Error())
+		return response[investmentUpdateCSVReportDto]{}, NewError(http.StatusBadRequest, err.Error())
 	}
 
 	csvFile, err := csvFormFile.Open()
 	if err != nil {
-		return response[empty]{}, fmt.Errorf("failed to open CSV file: %w", err)
+		return response[investmentUpdateCSVReportDto]{}, fmt.Errorf("failed to open CSV file: %w", err)
 	}
 	defer csvFile.Close()
 
-	err = h.investmentUpdateCSVService.Import(csv.NewReader(csvFile), investment)
+	if c.Query("dryRun") == "true" {
+		report := h.investmentUpdateCSVService.Validate(csv.NewReader(csvFile), investment, profile)
+		return newResponse(http.StatusOK, toInvestmentUpdateCSVReportDto(report, domain.ImportResult{}, false)), nil
+	}
+
+	report, result, err := h.investmentUpdateCSVService.Import(csv.NewReader(csvFile), investment, profile, strategy)
 	if err != nil {
-		return response[empty]{}, errors.Wrap(err, "failed to import CSV updates")
+		if err == ErrInvalidInvestmentUpdateCSV {
+			return newResponse(http.StatusUnprocessableEntity, toInvestmentUpdateCSVReportDto(report, result, false)), nil
+		}
+		if errors.Is(err, domain.ErrInvestmentUpdateExists) {
+			return response[investmentUpdateCSVReportDto]{}, NewError(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentIsLocked) {
+			return response[investmentUpdateCSVReportDto]{}, NewError(http.StatusForbidden, domain.ErrInvestmentIsLocked.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentIsClosed) {
+			return response[investmentUpdateCSVReportDto]{}, NewError(http.StatusConflict, domain.ErrInvestmentIsClosed.Error())
+		}
+		return response[investmentUpdateCSVReportDto]{}, errors.Wrap(err, "failed to import CSV updates")
 	}
 
-	return newEmptyResponse(200), nil
+	return newResponse(http.StatusOK, toInvestmentUpdateCSVReportDto(report, result, true)), nil
+}
+
+// ImportStatement imports the export of a broker in the "format", like "degiro", as updates of the investment. It works
+// like the CSV import: "dryRun" only parses the statement and "mergeStrategy" decides what happens to dates that
+// already have an update.
+func (h InvestmentHandler) ImportStatement(c *gin.Context) (response[investmentUpdateStatementReportDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[investmentUpdateStatementReportDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusForbidden, "not allowed to read investment")
+	}
+
+	strategy := domain.MergeStrategyFail
+	if c.Query("mergeStrategy") != "" {
+		strategy = domain.MergeStrategy(c.Query("mergeStrategy"))
+		if !strategy.IsValid() {
+			return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusBadRequest, "invalid merge strategy: "+string(strategy))
+		}
+	}
+
+	statementFormFile, err := c.FormFile("statementFile")
+	if err != nil {
+		return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	statementFile, err := statementFormFile.Open()
+	if err != nil {
+		return response[investmentUpdateStatementReportDto]{}, fmt.Errorf("failed to open statement file: %w", err)
+	}
+	defer statementFile.Close()
+
+	var commands []domain.CreateInvestmentUpdateCommand
+	var result domain.ImportResult
+	if c.Query("dryRun") == "true" {
+		commands, err = h.statementImporter.Validate(c.Query("format"), statementFile, investment)
+	} else {
+		commands, result, err = h.statementImporter.Import(c.Query("format"), statementFile, investment, strategy)
+	}
+	if err != nil {
+		if errors.Is(err, ErrUnknownStatementFormat) {
+			return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusBadRequest, fmt.Sprintf(
+				"%s, expected one of %s", err.Error(), strings.Join(h.statementImporter.Formats(), ", "),
+			))
+		}
+		if errors.Is(err, ErrInvalidStatement) {
+			return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusUnprocessableEntity, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentUpdateExists) {
+			return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentIsLocked) {
+			return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusForbidden, domain.ErrInvestmentIsLocked.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentIsClosed) {
+			return response[investmentUpdateStatementReportDto]{}, NewError(http.StatusConflict, domain.ErrInvestmentIsClosed.Error())
+		}
+		return response[investmentUpdateStatementReportDto]{}, errors.Wrap(err, "failed to import statement")
+	}
+
+	return newResponse(http.StatusOK, toInvestmentUpdateStatementReportDto(commands, result, c.Query("dryRun") != "true")), nil
 }
 
 func (h InvestmentHandler) ExportUpdates(c *gin.Context) error {
@@ -244,11 +554,12 @@ func (h InvestmentHandler) ExportUpdates(c *gin.Context) error {
 	csvWriter := csv.NewWriter(file)
 	defer csvWriter.Flush()
 
-	if err := csvWriter.Write([]string{"Date", "Deposit", "Withdrawal", "Value"}); err != nil {
+	if err := csvWriter.Write([]string{"Date", "Deposit", "Withdrawal", "Value", "Units", "Price", "Income", "Fee"}); err != nil {
 		return errors.Wrapf(err, "failed to write to tmp CSV file")
 	}
 	for _, record := range records {
-		if err := csvWriter.Write([]string{record.Date, record.Deposit, record.Withdrawal, record.Value}); err != nil {
+		row := []string{record.Date, record.Deposit, record.Withdrawal, record.Value, record.Units, record.Price, record.Income, record.Fee}
+		if err := csvWriter.Write(row); err != nil {
 			return errors.Wrapf(err, "failed to write to tmp CSV file")
 		}
 	}
@@ -264,19 +575,200 @@ func (h InvestmentHandler) ExportUpdates(c *gin.Context) error {
 	return nil
 }
 
-func toInvestmentDto(i domain.Investment) investmentDto {
+func (h InvestmentHandler) GetHoldings(c *gin.Context) (response[[]holdingDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[[]holdingDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[[]holdingDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[[]holdingDto]{}, NewError(http.StatusForbidden, "not allowed to read investment")
+	}
+
+	holdings, err := h.holdingService.FindByInvestmentID(investment.ID)
+	if err != nil {
+		return response[[]holdingDto]{}, errors.Wrapf(err, "failed to find holdings by investment id %s", id)
+	}
+
+	dtos := make([]holdingDto, 0)
+	for _, holding := range holdings {
+		dtos = append(dtos, toHoldingDto(holding))
+	}
+
+	return newResponse(http.StatusOK, dtos), nil
+}
+
+func toHoldingDto(h domain.Holding) holdingDto {
+	return newHoldingDto(
+		h.Date.Format("2006-01-02"),
+		h.Units,
+		h.Price,
+		h.CostBasis,
+		h.AverageCost,
+		h.UnrealizedGain,
+		h.UnrealizedGainPerUnit,
+	)
+}
+
+// toInvestmentDto tells since when the investment is stale, using the stale threshold of the user when the investment
+// has none of its own. The stale date is left out while the investment is still up to date.
+func toInvestmentDto(i domain.Investment, defaultStaleAfterDays int) investmentDto {
 	var lastUpdate *investmentUpdateDto
 	if i.LastUpdate != nil {
 		lastUpdate = pointer.Of(toInvestmentUpdateDto(*i.LastUpdate))
 	}
 
-	return newInvestmentDto(i.ID, i.Type, i.Name, i.Locked, lastUpdate)
+	now := time.Now()
+	var staleSince *time.Time
+	if i.IsStale(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), defaultStaleAfterDays) {
+		staleSince = i.StaleSince(defaultStaleAfterDays)
+	}
+
+	return newInvestmentDto(i.ID, i.Type, i.Name, i.Currency, i.AnnualFeePercentage, i.PortfolioID, i.Locked,
+		i.DisplayOrder, i.ClosedAt, i.StaleAfterDays, staleSince, i.TagIDs, lastUpdate)
+}
+
+func toInvestmentUpdateCSVReportDto(
+	r InvestmentUpdateCSVReport,
+	result domain.ImportResult,
+	imported bool,
+) investmentUpdateCSVReportDto {
+	rows := make([]investmentUpdateCSVRowDto, 0)
+	for _, row := range r.Rows {
+		rows = append(rows, newInvestmentUpdateCSVRowDto(
+			row.Row,
+			row.Command.Date.Format("2006-01-02"),
+			row.Command.Deposit,
+			row.Command.Withdrawal,
+			row.Command.Income,
+			row.Command.Fee,
+			row.Command.Value,
+			row.Command.Units,
+			row.Command.Price,
+		))
+	}
+
+	errs := make([]investmentUpdateCSVErrorDto, 0)
+	for _, e := range r.Errors {
+		errs = append(errs, investmentUpdateCSVErrorDto{Row: e.Row, Column: e.Column, Error: e.Message})
+	}
+
+	return investmentUpdateCSVReportDto{
+		Valid:    r.IsValid(),
+		Imported: imported,
+		Inserted: result.Inserted,
+		Updated:  result.Updated,
+		Skipped:  result.Skipped,
+		Rows:     rows,
+		Errors:   errs,
+	}
+}
+
+func toInvestmentUpdateStatementReportDto(
+	commands []domain.CreateInvestmentUpdateCommand,
+	result domain.ImportResult,
+	imported bool,
+) investmentUpdateStatementReportDto {
+	updates := make([]investmentUpdateStatementUpdateDto, 0)
+	for _, command := range commands {
+		updates = append(updates, investmentUpdateStatementUpdateDto{
+			Date:       command.Date.Format("2006-01-02"),
+			Deposit:    command.Deposit,
+			Withdrawal: command.Withdrawal,
+			Value:      command.Value,
+		})
+	}
+
+	return investmentUpdateStatementReportDto{
+		Imported: imported,
+		Inserted: result.Inserted,
+		Updated:  result.Updated,
+		Skipped:  result.Skipped,
+		Updates:  updates,
+	}
+}
+
+type investmentUpdateStatementReportDto struct {
+	Imported bool                                 `json:"imported"`
+	Inserted int                                  `json:"inserted"`
+	Updated  int                                  `json:"updated"`
+	Skipped  int                                  `json:"skipped"`
+	Updates  []investmentUpdateStatementUpdateDto `json:"updates"`
+}
+
+type investmentUpdateStatementUpdateDto struct {
+	Date       string `json:"date"`
+	Deposit    *int64 `json:"deposit"`
+	Withdrawal *int64 `json:"withdrawal"`
+	Value      int64  `json:"value"`
+}
+
+// investmentUpdateCSVReportDto previews the rows that could be parsed, with the value as given in the file.
+type investmentUpdateCSVReportDto struct {
+	Valid    bool                          `json:"valid"`
+	Imported bool                          `json:"imported"`
+	Inserted int                           `json:"inserted"`
+	Updated  int                           `json:"updated"`
+	Skipped  int                           `json:"skipped"`
+	Rows     []investmentUpdateCSVRowDto   `json:"rows"`
+	Errors   []investmentUpdateCSVErrorDto `json:"errors"`
+}
+
+type investmentUpdateCSVRowDto struct {
+	Row        int      `json:"row"`
+	Date       string   `json:"date"`
+	Deposit    *int64   `json:"deposit"`
+	Withdrawal *int64   `json:"withdrawal"`
+	Income     *int64   `json:"income"`
+	Fee        *int64   `json:"fee"`
+	Value      int64    `json:"value"`
+	Units      *float64 `json:"units"`
+	Price      *float64 `json:"price"`
+}
+
+func newInvestmentUpdateCSVRowDto(
+	row int,
+	date string,
+	deposit,
+	withdrawal,
+	income,
+	fee *int64,
+	value int64,
+	units,
+	price *float64,
+) investmentUpdateCSVRowDto {
+	return investmentUpdateCSVRowDto{
+		Row:        row,
+		Date:       date,
+		Deposit:    deposit,
+		Withdrawal: withdrawal,
+		Income:     income,
+		Fee:        fee,
+		Value:      value,
+		Units:      units,
+		Price:      price,
+	}
+}
+
+type investmentUpdateCSVErrorDto struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
 }
 
 type CreateInvestmentRequest struct {
-	Type          domain.InvestmentType    `json:"type"`
-	Name          string                   `json:"name"`
-	InitialUpdate *InitialInvestmentUpdate `json:"initialUpdate"`
+	Type                domain.InvestmentType    `json:"type"`
+	Name                string                   `json:"name"`
+	Currency            *string                  `json:"currency"`
+	AnnualFeePercentage *float64                 `json:"annualFeePercentage"`
+	InitialUpdate       *InitialInvestmentUpdate `json:"initialUpdate"`
 }
 
 func (r CreateInvestmentRequest) validate() error {
@@ -286,16 +778,41 @@ func (r CreateInvestmentRequest) validate() error {
 	if r.Name == "" {
 		return errors.New("field 'name' is missing")
 	}
+	if r.Currency != nil {
+		if _, err := domain.ParseCurrency(*r.Currency); err != nil {
+			return err
+		}
+	}
+	if r.AnnualFeePercentage != nil && (*r.AnnualFeePercentage < 0 || *r.AnnualFeePercentage > 100) {
+		return errors.New("field 'annualFeePercentage' must be between 0 and 100")
+	}
 	return nil
 }
 
+// closeInvestmentRequest defaults to closing today with the last known value.
+type closeInvestmentRequest struct {
+	Date       *string `json:"date"`
+	FinalValue *int64  `json:"finalValue"`
+}
+
+type patchInvestmentRequest struct {
+	Type           *domain.InvestmentType `json:"type"`
+	Name           *string                `json:"name"`
+	DisplayOrder   *int                   `json:"displayOrder"`
+	StaleAfterDays *int                   `json:"staleAfterDays"`
+}
+
+func (r patchInvestmentRequest) toCommand() domain.UpdateInvestmentCommand {
+	return domain.NewUpdateInvestmentCommand(r.Type, r.Name, r.DisplayOrder, r.StaleAfterDays)
+}
+
 type InitialInvestmentUpdate struct {
 	Date    *string `json:"date"`
 	Deposit *int64  `json:"deposit"`
 	Value   int64   `json:"value"`
 }
 
-func (r CreateInvestmentRequest) toCommand(user domain.User) (domain.CreateInvestmentCommand, error) {
+func (r CreateInvestmentRequest) toCommand(user domain.User, portfolio domain.Portfolio) (domain.CreateInvestmentCommand, error) {
 	var initialUpdate *domain.InitialInvestmentUpdate
 	if r.InitialUpdate != nil {
 		var date *time.Time
@@ -309,20 +826,34 @@ func (r CreateInvestmentRequest) toCommand(user domain.User) (domain.CreateInves
 		initialUpdate = pointer.Of(domain.NewInitialInvestmentUpdate(date, r.InitialUpdate.Deposit, r.InitialUpdate.Value))
 	}
 
+	var currency domain.Currency
+	if r.Currency != nil {
+		currency = domain.Currency(*r.Currency)
+	}
+
 	return domain.NewCreateInvestmentCommand(
 		r.Type,
 		r.Name,
+		currency,
+		r.AnnualFeePercentage,
 		user,
+		portfolio,
 		false,
 		initialUpdate,
 	), nil
 }
 
 type createInvestmentUpdateRequest struct {
-	Date       string `json:"date"`
-	Deposit    *int64 `json:"deposit"`
-	Withdrawal *int64 `json:"withdrawal"`
-	Value      int64  `json:"value"`
+	Date       string   `json:"date"`
+	Deposit    *int64   `json:"deposit"`
+	Withdrawal *int64   `json:"withdrawal"`
+	Income     *int64   `json:"income"`
+	Fee        *int64   `json:"fee"`
+	Value      int64    `json:"value"`
+	Units      *float64 `json:"units"`
+	Price      *float64 `json:"price"`
+	// FromTransactions takes the deposit, withdrawal and income from the transaction ledger instead of the request.
+	FromTransactions bool `json:"fromTransactions"`
 }
 
 func (r createInvestmentUpdateRequest) toCommand(investment domain.Investment) (domain.CreateInvestmentUpdateCommand, error) {
@@ -330,24 +861,119 @@ func (r createInvestmentUpdateRequest) toCommand(investment domain.Investment) (
 	if err != nil {
 		return domain.CreateInvestmentUpdateCommand{}, fmt.Errorf("failed to parse date: %w", err)
 	}
+	if r.Units != nil && *r.Units < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'units' cannot be negative")
+	}
+	if r.Price != nil && *r.Price < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'price' cannot be negative")
+	}
+
+	if r.Income != nil && *r.Income < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'income' cannot be negative")
+	}
+	if r.Fee != nil && *r.Fee < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'fee' cannot be negative")
+	}
+	if r.FromTransactions && (r.Deposit != nil || r.Withdrawal != nil || r.Income != nil) {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("fields 'deposit', 'withdrawal' and 'income' cannot be combined with 'fromTransactions'")
+	}
 
-	return domain.NewCreateInvestmentUpdateCommand(investment, date, r.Deposit, r.Withdrawal, r.Value), nil
+	return domain.NewCreateInvestmentUpdateCommand(
+		investment,
+		date,
+		r.Deposit,
+		r.Withdrawal,
+		r.Income,
+		r.Fee,
+		r.Value,
+		r.Units,
+		r.Price,
+	), nil
 }
 
 type investmentDto struct {
-	ID         string                `json:"id"`
-	Type       domain.InvestmentType `json:"type"`
-	Name       string                `json:"name"`
-	Locked     bool                  `json:"locked"`
-	LastUpdate *investmentUpdateDto  `json:"lastUpdate"`
+	ID                  string                `json:"id"`
+	Type                domain.InvestmentType `json:"type"`
+	Name                string                `json:"name"`
+	Currency            domain.Currency       `json:"currency"`
+	AnnualFeePercentage *float64              `json:"annualFeePercentage"`
+	PortfolioID         string                `json:"portfolioId"`
+	Locked              bool                  `json:"locked"`
+	DisplayOrder        int                   `json:"displayOrder"`
+	ClosedAt            *string               `json:"closedAt"`
+	StaleAfterDays      *int                  `json:"staleAfterDays"`
+	StaleSince          *string               `json:"staleSince"`
+	TagIDs              []string              `json:"tagIds"`
+	LastUpdate          *investmentUpdateDto  `json:"lastUpdate"`
 }
 
 func newInvestmentDto(
 	id string,
 	t domain.InvestmentType,
 	name string,
+	currency domain.Currency,
+	annualFeePercentage *float64,
+	portfolioID string,
 	locked bool,
+	displayOrder int,
+	closedAt *time.Time,
+	staleAfterDays *int,
+	staleSince *time.Time,
+	tagIDs []string,
 	lastUpdate *investmentUpdateDto,
 ) investmentDto {
-	return investmentDto{ID: id, Type: t, Name: name, Locked: locked, LastUpdate: lastUpdate}
+	var closedAtString *string
+	if closedAt != nil {
+		closedAtString = pointer.Of(closedAt.Format("2006-01-02"))
+	}
+	var staleSinceString *string
+	if staleSince != nil {
+		staleSinceString = pointer.Of(staleSince.Format("2006-01-02"))
+	}
+
+	return investmentDto{
+		ID:                  id,
+		Type:                t,
+		Name:                name,
+		Currency:            currency,
+		AnnualFeePercentage: annualFeePercentage,
+		PortfolioID:         portfolioID,
+		Locked:              locked,
+		DisplayOrder:        displayOrder,
+		ClosedAt:            closedAtString,
+		StaleAfterDays:      staleAfterDays,
+		StaleSince:          staleSinceString,
+		TagIDs:              tagIDs,
+		LastUpdate:          lastUpdate,
+	}
+}
+
+type holdingDto struct {
+	Date                  string  `json:"date"`
+	Units                 float64 `json:"units"`
+	Price                 float64 `json:"price"`
+	CostBasis             int64   `json:"costBasis"`
+	AverageCost           float64 `json:"averageCost"`
+	UnrealizedGain        int64   `json:"unrealizedGain"`
+	UnrealizedGainPerUnit float64 `json:"unrealizedGainPerUnit"`
+}
+
+func newHoldingDto(
+	date string,
+	units,
+	price float64,
+	costBasis int64,
+	averageCost float64,
+	unrealizedGain int64,
+	unrealizedGainPerUnit float64,
+) holdingDto {
+	return holdingDto{
+		Date:                  date,
+		Units:                 units,
+		Price:                 price,
+		CostBasis:             costBasis,
+		AverageCost:           averageCost,
+		UnrealizedGain:        unrealizedGain,
+		UnrealizedGainPerUnit: unrealizedGainPerUnit,
+	}
 }
@@ -2,12 +2,21 @@ package api
 
 import (
 	"encoding/csv"
+	"fmt"
 	"growfolio/internal/domain"
 	"growfolio/internal/domain/services"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 )
 
+// minInvestmentUpdateCSVColumns is the number of required columns without a column mapping: date, deposit, withdrawal
+// and value.
+const minInvestmentUpdateCSVColumns = 4
+
+var ErrInvalidInvestmentUpdateCSV = errors.New("CSV file has invalid rows")
+
 type InvestmentUpdateCSVImporter struct {
 	investmentUpdateService services.InvestmentUpdateService
 }
@@ -20,38 +29,181 @@ func NewInvestmentUpdateCSVImporter(
 	}
 }
 
-func (s InvestmentUpdateCSVImporter) Import(csvReader *csv.Reader, investment domain.Investment) error {
+// InvestmentUpdateCSVReport holds the rows that could be parsed and the errors of the rows that could not. Row numbers
+// are the line numbers in the file, so the header is row 1.
+type InvestmentUpdateCSVReport struct {
+	Rows   []InvestmentUpdateCSVRow
+	Errors []InvestmentUpdateCSVError
+}
+
+func (r InvestmentUpdateCSVReport) IsValid() bool {
+	return len(r.Errors) == 0
+}
+
+type InvestmentUpdateCSVRow struct {
+	Row     int
+	Command domain.CreateInvestmentUpdateCommand
+}
+
+// InvestmentUpdateCSVError has no column when the whole row is invalid.
+type InvestmentUpdateCSVError struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+func newInvestmentUpdateCSVError(row int, column, message string) InvestmentUpdateCSVError {
+	return InvestmentUpdateCSVError{
+		Row:     row,
+		Column:  column,
+		Message: message,
+	}
+}
+
+// Validate parses every row with the import profile without writing anything.
+func (s InvestmentUpdateCSVImporter) Validate(
+	csvReader *csv.Reader,
+	investment domain.Investment,
+	profile domain.ImportProfile,
+) InvestmentUpdateCSVReport {
+	csvReader.Comma, _ = utf8.DecodeRuneInString(profile.Delimiter)
+	csvReader.FieldsPerRecord = -1
+
+	report := InvestmentUpdateCSVReport{
+		Rows:   []InvestmentUpdateCSVRow{},
+		Errors: []InvestmentUpdateCSVError{},
+	}
+
 	stringRecords, err := csvReader.ReadAll()
 	if err != nil {
-		return errors.Wrap(err, "failed to read CSV records")
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Errors = append(report.Errors, newInvestmentUpdateCSVError(parseErr.Line, "", parseErr.Err.Error()))
+		} else {
+			report.Errors = append(report.Errors, newInvestmentUpdateCSVError(0, "", err.Error()))
+		}
+		return report
+	}
+	if len(stringRecords) == 0 {
+		return report
+	}
+
+	columns, minColumns, errs := findInvestmentUpdateCSVColumns(stringRecords[0], profile)
+	if len(errs) > 0 {
+		report.Errors = append(report.Errors, errs...)
+		return report
 	}
 
-	records := make([]InvestmentUpdateCSVRecord, 0)
 	for i := 1; i < len(stringRecords); i++ { // skipping the header row
 		stringRecord := stringRecords[i]
-		records = append(records, newInvestmentUpdateCSVRecord(
-			stringRecord[0],
-			stringRecord[1],
-			stringRecord[2],
-			stringRecord[3],
-		))
+		row := i + 1
+
+		if len(stringRecord) < minColumns {
+			report.Errors = append(report.Errors, newInvestmentUpdateCSVError(row, "", fmt.Sprintf(
+				"expected at least %d columns but got %d", minColumns, len(stringRecord),
+			)))
+			continue
+		}
+
+		// the columns that are not in the file are left empty
+		cell := func(field string) string {
+			index, ok := columns[field]
+			if !ok || index >= len(stringRecord) {
+				return ""
+			}
+			return stringRecord[index]
+		}
+
+		record := newInvestmentUpdateCSVRecord(
+			cell(domain.ImportFieldDate),
+			cell(domain.ImportFieldDeposit),
+			cell(domain.ImportFieldWithdrawal),
+			cell(domain.ImportFieldValue),
+			cell(domain.ImportFieldUnits),
+			cell(domain.ImportFieldPrice),
+			cell(domain.ImportFieldIncome),
+			cell(domain.ImportFieldFee),
+		)
+
+		command, errs := record.toCreateInvestmentUpdateCommand(investment, profile, row)
+		if len(errs) > 0 {
+			report.Errors = append(report.Errors, errs...)
+			continue
+		}
+		report.Rows = append(report.Rows, InvestmentUpdateCSVRow{Row: row, Command: command})
 	}
 
-	commands := make([]domain.CreateInvestmentUpdateCommand, 0)
-	for _, record := range records {
-		command, err := record.toCreateInvestmentUpdateCommand(investment)
-		if err != nil {
-			return errors.Wrap(err, "failed to map record to command")
+	return report
+}
+
+// findInvestmentUpdateCSVColumns returns the index of the column of every field and the number of columns that a row
+// needs. Without a column mapping, the columns are in the order of domain.ImportFields and only the first four are
+// required. With a column mapping, every mapped header has to be in the header row, compared without case.
+func findInvestmentUpdateCSVColumns(
+	header []string,
+	profile domain.ImportProfile,
+) (map[string]int, int, []InvestmentUpdateCSVError) {
+	columns := make(map[string]int)
+
+	if len(profile.ColumnMapping) == 0 {
+		for i, field := range domain.ImportFields {
+			columns[field] = i
+		}
+		return columns, minInvestmentUpdateCSVColumns, nil
+	}
+
+	indexes := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := indexes[name]; !ok {
+			indexes[name] = i
 		}
-		commands = append(commands, command)
 	}
 
-	for _, command := range commands {
-		_, err := s.investmentUpdateService.Create(command)
-		if err != nil {
-			return errors.Wrap(err, "failed to create update")
+	errs := make([]InvestmentUpdateCSVError, 0)
+	minColumns := 0
+	for _, field := range domain.ImportFields {
+		name, ok := profile.ColumnMapping[field]
+		if !ok {
+			continue
+		}
+		index, ok := indexes[strings.ToLower(name)]
+		if !ok {
+			errs = append(errs, newInvestmentUpdateCSVError(1, field, fmt.Sprintf("header %q not found", name)))
+			continue
 		}
+		columns[field] = index
+		minColumns = max(minColumns, index+1)
+	}
+
+	return columns, minColumns, errs
+}
+
+// Import only writes the updates when every row is valid, and then writes all of them together with the merge strategy
+// for dates that already have an update. Otherwise it returns the report with ErrInvalidInvestmentUpdateCSV.
+func (s InvestmentUpdateCSVImporter) Import(
+	csvReader *csv.Reader,
+	investment domain.Investment,
+	profile domain.ImportProfile,
+	strategy domain.MergeStrategy,
+) (InvestmentUpdateCSVReport, domain.ImportResult, error) {
+	report := s.Validate(csvReader, investment, profile)
+	if !report.IsValid() {
+		return report, domain.ImportResult{}, ErrInvalidInvestmentUpdateCSV
+	}
+	if len(report.Rows) == 0 {
+		return report, domain.ImportResult{}, nil
+	}
+
+	commands := make([]domain.CreateInvestmentUpdateCommand, 0)
+	for _, row := range report.Rows {
+		commands = append(commands, row.Command)
+	}
+
+	result, err := s.investmentUpdateService.Import(commands, strategy)
+	if err != nil {
+		return report, domain.ImportResult{}, errors.Wrap(err, "failed to import updates")
 	}
 
-	return nil
+	return report, result, nil
 }
@@ -5,7 +5,7 @@ import (
 	"growfolio/internal/domain"
 	"growfolio/internal/pointer"
 	"strconv"
-	"time"
+	"strings"
 )
 
 type InvestmentUpdateCSVRecord struct {
@@ -13,49 +13,114 @@ type InvestmentUpdateCSVRecord struct {
 	Deposit    string
 	Withdrawal string
 	Value      string
+	Units      string
+	Price      string
+	Income     string
+	Fee        string
 }
 
-func newInvestmentUpdateCSVRecord(date, deposit, withdrawal, value string) InvestmentUpdateCSVRecord {
+func newInvestmentUpdateCSVRecord(
+	date,
+	deposit,
+	withdrawal,
+	value,
+	units,
+	price,
+	income,
+	fee string,
+) InvestmentUpdateCSVRecord {
 	return InvestmentUpdateCSVRecord{
 		Date:       date,
 		Deposit:    deposit,
 		Withdrawal: withdrawal,
 		Value:      value,
+		Units:      units,
+		Price:      price,
+		Income:     income,
+		Fee:        fee,
 	}
 }
 
+// toCreateInvestmentUpdateCommand collects the errors of every column instead of stopping at the first one.
 func (r InvestmentUpdateCSVRecord) toCreateInvestmentUpdateCommand(
 	investment domain.Investment,
-) (domain.CreateInvestmentUpdateCommand, error) {
-	date, err := time.Parse("2006-01-02", r.Date)
+	profile domain.ImportProfile,
+	row int,
+) (domain.CreateInvestmentUpdateCommand, []InvestmentUpdateCSVError) {
+	errs := make([]InvestmentUpdateCSVError, 0)
+
+	date, err := profile.ParseDate(r.Date)
 	if err != nil {
-		return domain.CreateInvestmentUpdateCommand{}, fmt.Errorf("failed to parse date: %w", err)
+		errs = append(errs, newInvestmentUpdateCSVError(row, "date", fmt.Sprintf("failed to parse date: %s", err.Error())))
 	}
 
-	var deposit *int64
-	if r.Deposit != "" {
-		parsed, err := strconv.ParseInt(r.Deposit, 10, 64)
+	deposit := parseOptionalCSVAmount(profile, r.Deposit, row, "deposit", &errs)
+	withdrawal := parseOptionalCSVAmount(profile, r.Withdrawal, row, "withdrawal", &errs)
+	income := parseOptionalCSVAmount(profile, r.Income, row, "income", &errs)
+	fee := parseOptionalCSVAmount(profile, r.Fee, row, "fee", &errs)
+	units := parseOptionalCSVNumber(profile, r.Units, row, "units", &errs)
+	price := parseOptionalCSVNumber(profile, r.Price, row, "price", &errs)
+
+	// the value can be left out when it follows from the units and the price
+	var value int64
+	if strings.TrimSpace(r.Value) != "" || units == nil || price == nil {
+		value, err = profile.ParseAmount(r.Value)
 		if err != nil {
-			return domain.CreateInvestmentUpdateCommand{}, fmt.Errorf("failed to parse deposit: %w", err)
+			errs = append(errs, newInvestmentUpdateCSVError(row, "value", fmt.Sprintf("failed to parse value: %s", err.Error())))
 		}
-		deposit = &parsed
 	}
 
-	var withdrawal *int64
-	if r.Withdrawal != "" {
-		parsed, err := strconv.ParseInt(r.Withdrawal, 10, 64)
-		if err != nil {
-			return domain.CreateInvestmentUpdateCommand{}, fmt.Errorf("failed to parse withdrawal: %w", err)
-		}
-		withdrawal = &parsed
+	if len(errs) > 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errs
 	}
 
-	value, err := strconv.ParseInt(r.Value, 10, 64)
+	return domain.NewCreateInvestmentUpdateCommand(
+		investment,
+		date,
+		deposit,
+		withdrawal,
+		income,
+		fee,
+		value,
+		units,
+		price,
+	), nil
+}
+
+func parseOptionalCSVAmount(
+	profile domain.ImportProfile,
+	s string,
+	row int,
+	column string,
+	errs *[]InvestmentUpdateCSVError,
+) *int64 {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	parsed, err := profile.ParseAmount(s)
 	if err != nil {
-		return domain.CreateInvestmentUpdateCommand{}, fmt.Errorf("failed to parse value: %w", err)
+		*errs = append(*errs, newInvestmentUpdateCSVError(row, column, fmt.Sprintf("failed to parse %s: %s", column, err.Error())))
+		return nil
 	}
+	return &parsed
+}
 
-	return domain.NewCreateInvestmentUpdateCommand(investment, date, deposit, withdrawal, value), nil
+func parseOptionalCSVNumber(
+	profile domain.ImportProfile,
+	s string,
+	row int,
+	column string,
+	errs *[]InvestmentUpdateCSVError,
+) *float64 {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	parsed, err := profile.ParseNumber(s)
+	if err != nil {
+		*errs = append(*errs, newInvestmentUpdateCSVError(row, column, fmt.Sprintf("failed to parse %s: %s", column, err.Error())))
+		return nil
+	}
+	return &parsed
 }
 
 func toInvestmentUpdateCSVRecord(update domain.InvestmentUpdate) InvestmentUpdateCSVRecord {
@@ -64,5 +129,9 @@ func toInvestmentUpdateCSVRecord(update domain.InvestmentUpdate) InvestmentUpdat
 		pointer.IntToString(update.Deposit),
 		pointer.IntToString(update.Withdrawal),
 		strconv.FormatInt(update.Value, 10),
+		pointer.FloatToString(update.Units),
+		pointer.FloatToString(update.Price),
+		pointer.IntToString(update.Income),
+		pointer.IntToString(update.Fee),
 	)
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"growfolio/internal/domain"
 	"growfolio/internal/domain/services"
@@ -17,15 +18,18 @@ import (
 type InvestmentUpdateHandler struct {
 	investmentService       services.InvestmentService
 	investmentUpdateService services.InvestmentUpdateService
+	portfolioService        services.PortfolioService
 }
 
 func NewInvestmentUpdateHandler(
 	investmentService services.InvestmentService,
 	investmentUpdateService services.InvestmentUpdateService,
+	portfolioService services.PortfolioService,
 ) InvestmentUpdateHandler {
 	return InvestmentUpdateHandler{
 		investmentService:       investmentService,
 		investmentUpdateService: investmentUpdateService,
+		portfolioService:        portfolioService,
 	}
 }
 
@@ -43,10 +47,18 @@ func (h InvestmentUpdateHandler) GetInvestmentUpdates(c *gin.Context) (response[
 		dateFromFilter = &parsed
 	}
 
-	investments, err := h.investmentService.FindByUserID(tokenUserID)
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
 	if err != nil {
 		return response[[]investmentUpdateDto]{}, fmt.Errorf("failed to find investments: %w", err)
 	}
+	if tagIDFilter := c.Query("tagId"); tagIDFilter != "" {
+		investments = xslices.Filter(investments, func(i domain.Investment) bool { return i.HasTag(tagIDFilter) })
+	}
 	if len(investments) == 0 {
 		return newResponse(http.StatusOK, []investmentUpdateDto{}), nil
 	}
@@ -75,29 +87,154 @@ func (h InvestmentUpdateHandler) GetInvestmentUpdates(c *gin.Context) (response[
 	return newResponse(http.StatusOK, dtos), nil
 }
 
-func (h InvestmentUpdateHandler) DeleteInvestmentUpdate(c *gin.Context) (response[empty], error) {
+// CreateInvestmentUpdates creates an update on the same date for each of the investments in the request. The request is
+// rejected as a whole when any of the investments is not owned by the user, locked or closed.
+func (h InvestmentUpdateHandler) CreateInvestmentUpdates(c *gin.Context) (response[[]investmentUpdateDto], error) {
 	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
 	tokenUserID := tokenClaims["userId"].(string)
 
-	id := c.Param("id")
-	update, err := h.investmentUpdateService.FindByID(id)
+	var request createInvestmentUpdatesRequest
+	err := c.ShouldBindJSON(&request)
 	if err != nil {
-		if err == domain.ErrInvestmentUpdateNotFound {
-			return response[empty]{}, NewError(http.StatusNotFound, err.Error())
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	date, err := time.Parse("2006-01-02", request.Date)
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, "failed to parse date: "+err.Error())
+	}
+	if len(request.Entries) == 0 {
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, "field 'entries' cannot be empty")
+	}
+
+	commands := make([]domain.CreateInvestmentUpdateCommand, 0)
+	seen := make(map[string]bool)
+	for i, entry := range request.Entries {
+		if seen[entry.InvestmentID] {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, fmt.Sprintf("entry %d: duplicate investment %s", i, entry.InvestmentID))
 		}
-		return response[empty]{}, fmt.Errorf("failed to find investment update: %w", err)
+		seen[entry.InvestmentID] = true
+
+		investment, err := h.investmentService.FindByID(entry.InvestmentID)
+		if err != nil {
+			if err == domain.ErrInvestmentNotFound {
+				return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, fmt.Sprintf("entry %d: %s", i, err.Error()))
+			}
+			return response[[]investmentUpdateDto]{}, fmt.Errorf("failed to find investment: %w", err)
+		}
+
+		if investment.UserID != tokenUserID {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusForbidden, fmt.Sprintf("entry %d: not allowed to create update for investment", i))
+		}
+		if investment.Locked {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusForbidden, fmt.Sprintf("entry %d: %s", i, domain.ErrInvestmentIsLocked.Error()))
+		}
+		if investment.IsClosed() {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusConflict, fmt.Sprintf("entry %d: %s", i, domain.ErrInvestmentIsClosed.Error()))
+		}
+
+		commands = append(commands, domain.NewCreateInvestmentUpdateCommand(
+			investment,
+			date,
+			entry.Deposit,
+			entry.Withdrawal,
+			nil,
+			nil,
+			entry.Value,
+			nil,
+			nil,
+		))
 	}
 
-	investment, err := h.investmentService.FindByID(update.InvestmentID)
+	updates, err := h.investmentUpdateService.CreateAll(commands)
 	if err != nil {
-		if err == domain.ErrInvestmentNotFound {
-			return response[empty]{}, NewError(http.StatusBadRequest, err.Error())
+		if errors.Is(err, domain.ErrInvestmentUpdateExists) {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusConflict, err.Error())
+		}
+		return response[[]investmentUpdateDto]{}, fmt.Errorf("failed to create investment updates: %w", err)
+	}
+
+	return newResponse(http.StatusCreated, xslices.Map(updates, toInvestmentUpdateDto)), nil
+}
+
+func (h InvestmentUpdateHandler) UpdateInvestmentUpdate(c *gin.Context) (response[[]investmentUpdateDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request createInvestmentUpdateRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+	if request.FromTransactions {
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, "field 'fromTransactions' is only supported when creating an update")
+	}
+
+	update, investment, err := h.findOwnedUpdate(c.Param("id"), tokenUserID, "not allowed to update investment update")
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, err
+	}
+
+	command, err := request.toCommand(investment)
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	return h.update(update, command)
+}
+
+func (h InvestmentUpdateHandler) PatchInvestmentUpdate(c *gin.Context) (response[[]investmentUpdateDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request patchInvestmentUpdateRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	update, investment, err := h.findOwnedUpdate(c.Param("id"), tokenUserID, "not allowed to update investment update")
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, err
+	}
+
+	command, err := request.toCommand(update, investment)
+	if err != nil {
+		return response[[]investmentUpdateDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	return h.update(update, command)
+}
+
+func (h InvestmentUpdateHandler) update(
+	update domain.InvestmentUpdate,
+	command domain.CreateInvestmentUpdateCommand,
+) (response[[]investmentUpdateDto], error) {
+	updates, err := h.investmentUpdateService.Update(update, command)
+	if err != nil {
+		if err == domain.ErrInvestmentIsLocked {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusForbidden, err.Error())
+		}
+		if err == domain.ErrInvestmentIsClosed {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvestmentUpdateExists) {
+			return response[[]investmentUpdateDto]{}, NewError(http.StatusConflict, err.Error())
 		}
-		return response[empty]{}, fmt.Errorf("failed to find investment: %w", err)
+		return response[[]investmentUpdateDto]{}, fmt.Errorf("failed to update investment update: %w", err)
 	}
 
-	if investment.UserID != tokenUserID {
-		return response[empty]{}, NewError(http.StatusForbidden, "not allowed to delete investment update")
+	return newResponse(http.StatusOK, xslices.Map(updates, toInvestmentUpdateDto)), nil
+}
+
+func (h InvestmentUpdateHandler) DeleteInvestmentUpdate(c *gin.Context) (response[empty], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	id := c.Param("id")
+	_, _, err := h.findOwnedUpdate(id, tokenUserID, "not allowed to delete investment update")
+	if err != nil {
+		return response[empty]{}, err
 	}
 
 	err = h.investmentUpdateService.DeleteByID(id)
@@ -108,28 +245,180 @@ func (h InvestmentUpdateHandler) DeleteInvestmentUpdate(c *gin.Context) (respons
 	return newEmptyResponse(http.StatusNoContent), nil
 }
 
-func toInvestmentUpdateDto(u domain.InvestmentUpdate) investmentUpdateDto {
-	return newInvestmentUpdateDto(u.ID, u.Date.Format("2006-01-02"), u.InvestmentID, u.Deposit, u.Withdrawal, u.Cost, u.Value)
+func (h InvestmentUpdateHandler) findOwnedUpdate(
+	id,
+	userID,
+	forbiddenMessage string,
+) (domain.InvestmentUpdate, domain.Investment, error) {
+	update, err := h.investmentUpdateService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentUpdateNotFound {
+			return domain.InvestmentUpdate{}, domain.Investment{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.InvestmentUpdate{}, domain.Investment{}, fmt.Errorf("failed to find investment update: %w", err)
+	}
+
+	investment, err := h.investmentService.FindByID(update.InvestmentID)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return domain.InvestmentUpdate{}, domain.Investment{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return domain.InvestmentUpdate{}, domain.Investment{}, fmt.Errorf("failed to find investment: %w", err)
+	}
+
+	if investment.UserID != userID {
+		return domain.InvestmentUpdate{}, domain.Investment{}, NewError(http.StatusForbidden, forbiddenMessage)
+	}
+
+	return update, investment, nil
 }
 
-type investmentUpdateDto struct {
-	ID           string `json:"id"`
+type createInvestmentUpdatesRequest struct {
+	Date    string                         `json:"date"`
+	Entries []createInvestmentUpdatesEntry `json:"entries"`
+}
+
+type createInvestmentUpdatesEntry struct {
 	InvestmentID string `json:"investmentId"`
-	Date         string `json:"date"`
 	Deposit      *int64 `json:"deposit"`
 	Withdrawal   *int64 `json:"withdrawal"`
-	Cost         int64  `json:"cost"`
 	Value        int64  `json:"value"`
 }
 
-func newInvestmentUpdateDto(id, date, investmentId string, deposit, withdrawal *int64, cost, value int64) investmentUpdateDto {
+// patchInvestmentUpdateRequest only changes the fields that are present. An explicit null clears the deposit,
+// withdrawal, income, fee, units or price. When the value changes without a new price, the price is derived again
+// from the value.
+type patchInvestmentUpdateRequest struct {
+	Date       *string           `json:"date"`
+	Deposit    nullable[int64]   `json:"deposit"`
+	Withdrawal nullable[int64]   `json:"withdrawal"`
+	Income     nullable[int64]   `json:"income"`
+	Fee        nullable[int64]   `json:"fee"`
+	Value      *int64            `json:"value"`
+	Units      nullable[float64] `json:"units"`
+	Price      nullable[float64] `json:"price"`
+}
+
+func (r patchInvestmentUpdateRequest) toCommand(
+	update domain.InvestmentUpdate,
+	investment domain.Investment,
+) (domain.CreateInvestmentUpdateCommand, error) {
+	command := domain.NewCreateInvestmentUpdateCommand(
+		investment,
+		update.Date,
+		update.Deposit,
+		update.Withdrawal,
+		update.Income,
+		update.Fee,
+		update.Value,
+		update.Units,
+		update.Price,
+	)
+
+	if r.Date != nil {
+		date, err := time.Parse("2006-01-02", *r.Date)
+		if err != nil {
+			return domain.CreateInvestmentUpdateCommand{}, fmt.Errorf("failed to parse date: %w", err)
+		}
+		command.Date = date
+	}
+	if r.Income.Value != nil && *r.Income.Value < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'income' cannot be negative")
+	}
+	if r.Fee.Value != nil && *r.Fee.Value < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'fee' cannot be negative")
+	}
+	if r.Units.Value != nil && *r.Units.Value < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'units' cannot be negative")
+	}
+	if r.Price.Value != nil && *r.Price.Value < 0 {
+		return domain.CreateInvestmentUpdateCommand{}, errors.New("field 'price' cannot be negative")
+	}
+
+	if r.Deposit.Present {
+		command.Deposit = r.Deposit.Value
+	}
+	if r.Withdrawal.Present {
+		command.Withdrawal = r.Withdrawal.Value
+	}
+	if r.Income.Present {
+		command.Income = r.Income.Value
+	}
+	if r.Fee.Present {
+		command.Fee = r.Fee.Value
+	}
+	if r.Value != nil {
+		command.Value = *r.Value
+		command.Price = nil
+	}
+	if r.Units.Present {
+		command.Units = r.Units.Value
+	}
+	if r.Price.Present {
+		command.Price = r.Price.Value
+	}
+
+	return command, nil
+}
+
+func toInvestmentUpdateDto(u domain.InvestmentUpdate) investmentUpdateDto {
+	return newInvestmentUpdateDto(
+		u.ID,
+		u.Date.Format("2006-01-02"),
+		u.InvestmentID,
+		u.Deposit,
+		u.Withdrawal,
+		u.Income,
+		u.Fee,
+		u.Cost,
+		u.Fees,
+		u.Value,
+		u.Units,
+		u.Price,
+	)
+}
+
+type investmentUpdateDto struct {
+	ID           string   `json:"id"`
+	InvestmentID string   `json:"investmentId"`
+	Date         string   `json:"date"`
+	Deposit      *int64   `json:"deposit"`
+	Withdrawal   *int64   `json:"withdrawal"`
+	Income       *int64   `json:"income"`
+	Fee          *int64   `json:"fee"`
+	Cost         int64    `json:"cost"`
+	Fees         int64    `json:"fees"`
+	Value        int64    `json:"value"`
+	Units        *float64 `json:"units"`
+	Price        *float64 `json:"price"`
+}
+
+func newInvestmentUpdateDto(
+	id,
+	date,
+	investmentId string,
+	deposit,
+	withdrawal,
+	income,
+	fee *int64,
+	cost,
+	fees,
+	value int64,
+	units,
+	price *float64,
+) investmentUpdateDto {
 	return investmentUpdateDto{
 		ID:           id,
 		InvestmentID: investmentId,
 		Date:         date,
 		Deposit:      deposit,
 		Withdrawal:   withdrawal,
+		Income:       income,
+		Fee:          fee,
 		Cost:         cost,
+		Fees:         fees,
 		Value:        value,
+		Units:        units,
+		Price:        price,
 	}
 }
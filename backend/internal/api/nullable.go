@@ -0,0 +1,26 @@
+package api
+
+import "encoding/json"
+
+// nullable is a field of a patch request that tells an absent field apart from an explicit null. An absent field is
+// left as it is, while null clears it.
+type nullable[T any] struct {
+	Present bool
+	Value   *T
+}
+
+func (n *nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Present = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	var value T
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
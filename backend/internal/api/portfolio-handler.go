@@ -0,0 +1,766 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/pointer"
+	xslices "growfolio/internal/slices"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type PortfolioHandler struct {
+	investmentService services.InvestmentService
+	portfolioService  services.PortfolioService
+	feeService        services.FeeService
+	gainService       services.GainService
+	tagService        services.TagService
+}
+
+func NewPortfolioHandler(
+	investmentService services.InvestmentService,
+	portfolioService services.PortfolioService,
+	feeService services.FeeService,
+	gainService services.GainService,
+	tagService services.TagService,
+) PortfolioHandler {
+	return PortfolioHandler{
+		investmentService: investmentService,
+		portfolioService:  portfolioService,
+		feeService:        feeService,
+		gainService:       gainService,
+		tagService:        tagService,
+	}
+}
+
+func (h PortfolioHandler) GetPortfolios(c *gin.Context) (response[[]portfolioDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	portfolios, err := h.portfolioService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[[]portfolioDto]{}, fmt.Errorf("failed to find portfolios: %w", err)
+	}
+
+	return newResponse(http.StatusOK, xslices.Map(portfolios, toPortfolioDto)), nil
+}
+
+func (h PortfolioHandler) CreatePortfolio(c *gin.Context) (response[portfolioDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request createPortfolioRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[portfolioDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var currency domain.Currency
+	if request.Currency != nil {
+		currency, err = domain.ParseCurrency(*request.Currency)
+		if err != nil {
+			return response[portfolioDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	created, err := h.portfolioService.Create(tokenUserID, request.Name, currency)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPortfolio) {
+			return response[portfolioDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[portfolioDto]{}, fmt.Errorf("failed to create portfolio: %w", err)
+	}
+
+	return newResponse(http.StatusCreated, toPortfolioDto(created)), nil
+}
+
+func (h PortfolioHandler) PatchPortfolio(c *gin.Context) (response[portfolioDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request patchPortfolioRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[portfolioDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	command := domain.UpdatePortfolioCommand{Name: request.Name}
+	if request.Currency != nil {
+		currency, err := domain.ParseCurrency(*request.Currency)
+		if err != nil {
+			return response[portfolioDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		command.Currency = &currency
+	}
+
+	portfolio, err := h.findOwnedPortfolio(c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[portfolioDto]{}, err
+	}
+
+	updated, err := h.portfolioService.Update(portfolio, command)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPortfolio) {
+			return response[portfolioDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[portfolioDto]{}, fmt.Errorf("failed to update portfolio: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toPortfolioDto(updated)), nil
+}
+
+func (h PortfolioHandler) DeletePortfolio(c *gin.Context) (response[empty], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	portfolio, err := h.findOwnedPortfolio(c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[empty]{}, err
+	}
+
+	err = h.portfolioService.DeleteByID(portfolio)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPortfolio) {
+			return response[empty]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[empty]{}, fmt.Errorf("failed to delete portfolio: %w", err)
+	}
+
+	return newEmptyResponse(http.StatusNoContent), nil
+}
+
+func (h PortfolioHandler) findOwnedPortfolio(id, userID string) (domain.Portfolio, error) {
+	portfolio, err := h.portfolioService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrPortfolioNotFound {
+			return domain.Portfolio{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.Portfolio{}, fmt.Errorf("failed to find portfolio: %w", err)
+	}
+
+	if portfolio.UserID != userID {
+		return domain.Portfolio{}, NewError(http.StatusForbidden, "not allowed to modify portfolio")
+	}
+
+	return portfolio, nil
+}
+
+func (h PortfolioHandler) GetHistory(c *gin.Context) (response[[]portfolioHistoryPointDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	query, err := parseHistoryQuery(c)
+	if err != nil {
+		return response[[]portfolioHistoryPointDto]{}, err
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]portfolioHistoryPointDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]portfolioHistoryPointDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	query.Investments = investments
+	query.Currency = portfolio.Currency
+
+	points, err := h.portfolioService.FindHistory(query)
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[[]portfolioHistoryPointDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		if errors.Is(err, domain.ErrTooManyHistoryPoints) {
+			return response[[]portfolioHistoryPointDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[[]portfolioHistoryPointDto]{}, fmt.Errorf("failed to find portfolio history: %w", err)
+	}
+
+	return newResponse(http.StatusOK, xslices.Map(points, toPortfolioHistoryPointDto)), nil
+}
+
+// GetHistoryByTag returns the history of every tag group, with the untagged investments in the last group.
+func (h PortfolioHandler) GetHistoryByTag(c *gin.Context) (response[[]tagHistoryDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	query, err := parseHistoryQuery(c)
+	if err != nil {
+		return response[[]tagHistoryDto]{}, err
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]tagHistoryDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]tagHistoryDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	groups, err := h.tagService.GroupByTag(tokenUserID, investments)
+	if err != nil {
+		return response[[]tagHistoryDto]{}, fmt.Errorf("failed to group investments by tag: %w", err)
+	}
+
+	dtos := make([]tagHistoryDto, 0)
+	for _, group := range groups {
+		query.Investments = group.Investments
+		query.Currency = portfolio.Currency
+
+		points, err := h.portfolioService.FindHistory(query)
+		if err != nil {
+			if errors.Is(err, domain.ErrFXRateNotFound) {
+				return response[[]tagHistoryDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+			}
+			if errors.Is(err, domain.ErrTooManyHistoryPoints) {
+				return response[[]tagHistoryDto]{}, NewError(http.StatusBadRequest, err.Error())
+			}
+			return response[[]tagHistoryDto]{}, fmt.Errorf("failed to find portfolio history: %w", err)
+		}
+
+		dtos = append(dtos, newTagHistoryDto(toTagDtoOrNil(group.Tag), xslices.Map(points, toPortfolioHistoryPointDto)))
+	}
+
+	return newResponse(http.StatusOK, dtos), nil
+}
+
+func parseHistoryQuery(c *gin.Context) (domain.FindPortfolioHistoryQuery, error) {
+	interval, err := parseIntervalQuery(c)
+	if err != nil {
+		return domain.FindPortfolioHistoryQuery{}, err
+	}
+
+	dateFrom, err := parseDateQuery(c, "dateFrom")
+	if err != nil {
+		return domain.FindPortfolioHistoryQuery{}, err
+	}
+	dateTo, err := parseDateQuery(c, "dateTo")
+	if err != nil {
+		return domain.FindPortfolioHistoryQuery{}, err
+	}
+	if dateFrom != nil && dateTo != nil && dateFrom.After(*dateTo) {
+		return domain.FindPortfolioHistoryQuery{}, NewError(http.StatusBadRequest, "dateFrom is after dateTo")
+	}
+
+	return domain.FindPortfolioHistoryQuery{
+		Interval:            interval,
+		DateFrom:            dateFrom,
+		DateTo:              dateTo,
+		ExcludeFeesFromCost: c.Query("excludeFees") == "true",
+	}, nil
+}
+
+func (h PortfolioHandler) GetAllocation(c *gin.Context) (response[allocationReportDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	asOf, err := parseDateQuery(c, "asOf")
+	if err != nil {
+		return response[allocationReportDto]{}, err
+	}
+	if asOf == nil {
+		now := time.Now()
+		asOf = pointer.Of(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	}
+
+	interval, err := parseIntervalQuery(c)
+	if err != nil {
+		return response[allocationReportDto]{}, err
+	}
+	dateFrom, err := parseDateQuery(c, "dateFrom")
+	if err != nil {
+		return response[allocationReportDto]{}, err
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[allocationReportDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[allocationReportDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	allocation, err := h.portfolioService.FindAllocation(investments, portfolio.Currency, *asOf)
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[allocationReportDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		return response[allocationReportDto]{}, fmt.Errorf("failed to find allocation: %w", err)
+	}
+
+	history, err := h.portfolioService.FindAllocationHistory(domain.FindAllocationHistoryQuery{
+		Investments: investments,
+		Currency:    portfolio.Currency,
+		Interval:    interval,
+		DateFrom:    dateFrom,
+		DateTo:      asOf,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[allocationReportDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		if errors.Is(err, domain.ErrTooManyHistoryPoints) {
+			return response[allocationReportDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[allocationReportDto]{}, fmt.Errorf("failed to find allocation history: %w", err)
+	}
+
+	return newResponse(http.StatusOK, newAllocationReportDto(
+		toAllocationDto(allocation),
+		xslices.Map(history, toAllocationDto),
+	)), nil
+}
+
+func (h PortfolioHandler) GetAllocationByTag(c *gin.Context) (response[tagAllocationDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	asOf, err := parseDateQuery(c, "asOf")
+	if err != nil {
+		return response[tagAllocationDto]{}, err
+	}
+	if asOf == nil {
+		now := time.Now()
+		asOf = pointer.Of(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[tagAllocationDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[tagAllocationDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	groups, err := h.tagService.GroupByTag(tokenUserID, investments)
+	if err != nil {
+		return response[tagAllocationDto]{}, fmt.Errorf("failed to group investments by tag: %w", err)
+	}
+
+	allocation, err := h.portfolioService.FindAllocationByTag(investments, groups, portfolio.Currency, *asOf)
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[tagAllocationDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		return response[tagAllocationDto]{}, fmt.Errorf("failed to find allocation by tag: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toTagAllocationDto(allocation)), nil
+}
+
+func (h PortfolioHandler) GetIncome(c *gin.Context) (response[incomeDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	dateFrom, err := parseDateQuery(c, "dateFrom")
+	if err != nil {
+		return response[incomeDto]{}, err
+	}
+	dateTo, err := parseDateQuery(c, "dateTo")
+	if err != nil {
+		return response[incomeDto]{}, err
+	}
+	if dateFrom != nil && dateTo != nil && dateFrom.After(*dateTo) {
+		return response[incomeDto]{}, NewError(http.StatusBadRequest, "dateFrom is after dateTo")
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[incomeDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[incomeDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	income, err := h.portfolioService.FindIncome(domain.FindIncomeQuery{
+		Investments: investments,
+		Currency:    portfolio.Currency,
+		DateFrom:    dateFrom,
+		DateTo:      dateTo,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[incomeDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		return response[incomeDto]{}, fmt.Errorf("failed to find income: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toIncomeDto(income)), nil
+}
+
+func (h PortfolioHandler) GetFees(c *gin.Context) (response[[]investmentFeesDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	dateTo, err := parseDateQuery(c, "dateTo")
+	if err != nil {
+		return response[[]investmentFeesDto]{}, err
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]investmentFeesDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]investmentFeesDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	fees, err := h.feeService.FindFees(investments, dateTo)
+	if err != nil {
+		return response[[]investmentFeesDto]{}, fmt.Errorf("failed to find fees: %w", err)
+	}
+
+	return newResponse(http.StatusOK, xslices.Map(fees, toInvestmentFeesDto)), nil
+}
+
+func (h PortfolioHandler) GetGains(c *gin.Context) (response[[]gainsDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	method := domain.GainAttributionMethodProportional
+	if c.Query("method") != "" {
+		method = domain.GainAttributionMethod(c.Query("method"))
+		if !method.IsValid() {
+			return response[[]gainsDto]{}, NewError(http.StatusBadRequest, "invalid method: "+c.Query("method"))
+		}
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]gainsDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]gainsDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	gains, err := h.gainService.FindGains(investments, method)
+	if err != nil {
+		return response[[]gainsDto]{}, fmt.Errorf("failed to find gains: %w", err)
+	}
+
+	return newResponse(http.StatusOK, xslices.Map(gains, toGainsDto)), nil
+}
+
+type createPortfolioRequest struct {
+	Name     string  `json:"name"`
+	Currency *string `json:"currency"`
+}
+
+type patchPortfolioRequest struct {
+	Name     *string `json:"name"`
+	Currency *string `json:"currency"`
+}
+
+func toPortfolioDto(p domain.Portfolio) portfolioDto {
+	return newPortfolioDto(p.ID, p.Name, p.Currency)
+}
+
+type portfolioDto struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Currency domain.Currency `json:"currency"`
+}
+
+func newPortfolioDto(id, name string, currency domain.Currency) portfolioDto {
+	return portfolioDto{
+		ID:       id,
+		Name:     name,
+		Currency: currency,
+	}
+}
+
+func toPortfolioHistoryPointDto(p domain.PortfolioHistoryPoint) portfolioHistoryPointDto {
+	return newPortfolioHistoryPointDto(p.Date.Format("2006-01-02"), p.Cost, p.Value)
+}
+
+type portfolioHistoryPointDto struct {
+	Date  string `json:"date"`
+	Cost  int64  `json:"cost"`
+	Value int64  `json:"value"`
+}
+
+func newPortfolioHistoryPointDto(date string, cost, value int64) portfolioHistoryPointDto {
+	return portfolioHistoryPointDto{
+		Date:  date,
+		Cost:  cost,
+		Value: value,
+	}
+}
+
+func toAllocationDto(a domain.Allocation) allocationDto {
+	entries := xslices.Map(a.Entries, func(e domain.AllocationEntry) allocationEntryDto {
+		return newAllocationEntryDto(e.Type, e.Value, e.Percentage)
+	})
+	return newAllocationDto(a.AsOf.Format("2006-01-02"), a.Total, entries)
+}
+
+type allocationReportDto struct {
+	Current allocationDto   `json:"current"`
+	History []allocationDto `json:"history"`
+}
+
+func newAllocationReportDto(current allocationDto, history []allocationDto) allocationReportDto {
+	return allocationReportDto{
+		Current: current,
+		History: history,
+	}
+}
+
+type allocationDto struct {
+	Date    string               `json:"date"`
+	Total   int64                `json:"total"`
+	Entries []allocationEntryDto `json:"entries"`
+}
+
+func newAllocationDto(date string, total int64, entries []allocationEntryDto) allocationDto {
+	return allocationDto{
+		Date:    date,
+		Total:   total,
+		Entries: entries,
+	}
+}
+
+type allocationEntryDto struct {
+	Type       domain.InvestmentType `json:"type"`
+	Value      int64                 `json:"value"`
+	Percentage float64               `json:"percentage"`
+}
+
+func newAllocationEntryDto(t domain.InvestmentType, value int64, percentage float64) allocationEntryDto {
+	return allocationEntryDto{
+		Type:       t,
+		Value:      value,
+		Percentage: percentage,
+	}
+}
+
+type tagHistoryDto struct {
+	Tag    *tagDto                    `json:"tag"`
+	Points []portfolioHistoryPointDto `json:"points"`
+}
+
+func newTagHistoryDto(tag *tagDto, points []portfolioHistoryPointDto) tagHistoryDto {
+	return tagHistoryDto{
+		Tag:    tag,
+		Points: points,
+	}
+}
+
+func toTagAllocationDto(a domain.TagAllocation) tagAllocationDto {
+	entries := xslices.Map(a.Entries, func(e domain.TagAllocationEntry) tagAllocationEntryDto {
+		return newTagAllocationEntryDto(toTagDtoOrNil(e.Tag), e.Value, e.Percentage)
+	})
+	return newTagAllocationDto(a.AsOf.Format("2006-01-02"), a.Total, entries)
+}
+
+type tagAllocationDto struct {
+	Date    string                  `json:"date"`
+	Total   int64                   `json:"total"`
+	Entries []tagAllocationEntryDto `json:"entries"`
+}
+
+func newTagAllocationDto(date string, total int64, entries []tagAllocationEntryDto) tagAllocationDto {
+	return tagAllocationDto{
+		Date:    date,
+		Total:   total,
+		Entries: entries,
+	}
+}
+
+type tagAllocationEntryDto struct {
+	Tag        *tagDto `json:"tag"`
+	Value      int64   `json:"value"`
+	Percentage float64 `json:"percentage"`
+}
+
+func newTagAllocationEntryDto(tag *tagDto, value int64, percentage float64) tagAllocationEntryDto {
+	return tagAllocationEntryDto{
+		Tag:        tag,
+		Value:      value,
+		Percentage: percentage,
+	}
+}
+
+func toIncomeDto(i domain.Income) incomeDto {
+	toIncomePeriodDto := func(p domain.IncomePeriod) incomePeriodDto {
+		return newIncomePeriodDto(p.Date.Format("2006-01-02"), p.Amount)
+	}
+	byInvestment := xslices.Map(i.ByInvestment, func(e domain.InvestmentIncome) investmentIncomeDto {
+		return newInvestmentIncomeDto(e.InvestmentID, e.Amount)
+	})
+	return newIncomeDto(i.Total, xslices.Map(i.ByMonth, toIncomePeriodDto), xslices.Map(i.ByYear, toIncomePeriodDto), byInvestment)
+}
+
+type incomeDto struct {
+	Total        int64                 `json:"total"`
+	ByMonth      []incomePeriodDto     `json:"byMonth"`
+	ByYear       []incomePeriodDto     `json:"byYear"`
+	ByInvestment []investmentIncomeDto `json:"byInvestment"`
+}
+
+func newIncomeDto(
+	total int64,
+	byMonth,
+	byYear []incomePeriodDto,
+	byInvestment []investmentIncomeDto,
+) incomeDto {
+	return incomeDto{
+		Total:        total,
+		ByMonth:      byMonth,
+		ByYear:       byYear,
+		ByInvestment: byInvestment,
+	}
+}
+
+type incomePeriodDto struct {
+	Date   string `json:"date"`
+	Amount int64  `json:"amount"`
+}
+
+func newIncomePeriodDto(date string, amount int64) incomePeriodDto {
+	return incomePeriodDto{
+		Date:   date,
+		Amount: amount,
+	}
+}
+
+type investmentIncomeDto struct {
+	InvestmentID string `json:"investmentId"`
+	Amount       int64  `json:"amount"`
+}
+
+func newInvestmentIncomeDto(investmentID string, amount int64) investmentIncomeDto {
+	return investmentIncomeDto{
+		InvestmentID: investmentID,
+		Amount:       amount,
+	}
+}
+
+func toInvestmentFeesDto(f domain.InvestmentFees) investmentFeesDto {
+	return newInvestmentFeesDto(
+		f.InvestmentID,
+		f.OneOffFees,
+		f.RecurringFees,
+		f.Total(),
+		f.Taxes,
+		f.ReturnBeforeFees,
+		f.ReturnAfterFees,
+		f.Drag(),
+	)
+}
+
+type investmentFeesDto struct {
+	InvestmentID     string  `json:"investmentId"`
+	OneOffFees       int64   `json:"oneOffFees"`
+	RecurringFees    int64   `json:"recurringFees"`
+	TotalFees        int64   `json:"totalFees"`
+	Taxes            int64   `json:"taxes"`
+	ReturnBeforeFees float64 `json:"returnBeforeFees"`
+	ReturnAfterFees  float64 `json:"returnAfterFees"`
+	Drag             float64 `json:"drag"`
+}
+
+func newInvestmentFeesDto(
+	investmentID string,
+	oneOffFees,
+	recurringFees,
+	totalFees,
+	taxes int64,
+	returnBeforeFees,
+	returnAfterFees,
+	drag float64,
+) investmentFeesDto {
+	return investmentFeesDto{
+		InvestmentID:     investmentID,
+		OneOffFees:       oneOffFees,
+		RecurringFees:    recurringFees,
+		TotalFees:        totalFees,
+		Taxes:            taxes,
+		ReturnBeforeFees: returnBeforeFees,
+		ReturnAfterFees:  returnAfterFees,
+		Drag:             drag,
+	}
+}
+
+func toGainsDto(g domain.Gains) gainsDto {
+	withdrawals := xslices.Map(g.Withdrawals, func(w domain.AttributedWithdrawal) attributedWithdrawalDto {
+		return newAttributedWithdrawalDto(w.Date.Format("2006-01-02"), w.Amount, w.Principal, w.Gain)
+	})
+	byYear := xslices.Map(g.ByYear, func(y domain.YearlyGains) yearlyGainsDto {
+		return newYearlyGainsDto(y.Year, y.Realized, y.Unrealized)
+	})
+	return newGainsDto(g.InvestmentID, g.Realized, g.Unrealized, withdrawals, byYear)
+}
+
+type gainsDto struct {
+	InvestmentID string                    `json:"investmentId"`
+	Realized     int64                     `json:"realized"`
+	Unrealized   int64                     `json:"unrealized"`
+	Withdrawals  []attributedWithdrawalDto `json:"withdrawals"`
+	ByYear       []yearlyGainsDto          `json:"byYear"`
+}
+
+func newGainsDto(
+	investmentID string,
+	realized,
+	unrealized int64,
+	withdrawals []attributedWithdrawalDto,
+	byYear []yearlyGainsDto,
+) gainsDto {
+	return gainsDto{
+		InvestmentID: investmentID,
+		Realized:     realized,
+		Unrealized:   unrealized,
+		Withdrawals:  withdrawals,
+		ByYear:       byYear,
+	}
+}
+
+type attributedWithdrawalDto struct {
+	Date      string `json:"date"`
+	Amount    int64  `json:"amount"`
+	Principal int64  `json:"principal"`
+	Gain      int64  `json:"gain"`
+}
+
+func newAttributedWithdrawalDto(date string, amount, principal, gain int64) attributedWithdrawalDto {
+	return attributedWithdrawalDto{
+		Date:      date,
+		Amount:    amount,
+		Principal: principal,
+		Gain:      gain,
+	}
+}
+
+type yearlyGainsDto struct {
+	Year       int   `json:"year"`
+	Realized   int64 `json:"realized"`
+	Unrealized int64 `json:"unrealized"`
+}
+
+func newYearlyGainsDto(year int, realized, unrealized int64) yearlyGainsDto {
+	return yearlyGainsDto{
+		Year:       year,
+		Realized:   realized,
+		Unrealized: unrealized,
+	}
+}
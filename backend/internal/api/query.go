@@ -0,0 +1,66 @@
+package api
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseDateQuery parses an optional query parameter in the "2006-01-02" format.
+func parseDateQuery(c *gin.Context, key string) (*time.Time, error) {
+	if c.Query(key) == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", c.Query(key))
+	if err != nil {
+		return nil, NewError(http.StatusBadRequest, fmt.Sprintf("failed to parse %s: %s", key, err.Error()))
+	}
+	return &parsed, nil
+}
+
+// parseIntervalQuery parses the optional "interval" query parameter, which defaults to month.
+func parseIntervalQuery(c *gin.Context) (domain.Interval, error) {
+	if c.Query("interval") == "" {
+		return domain.IntervalMonth, nil
+	}
+
+	interval := domain.Interval(c.Query("interval"))
+	if !interval.IsValid() {
+		return "", NewError(http.StatusBadRequest, "invalid interval: "+string(interval))
+	}
+	return interval, nil
+}
+
+// findPortfolioQuery finds the portfolio in the optional "portfolioId" query parameter, which defaults to the first
+// portfolio of the user.
+func findPortfolioQuery(
+	c *gin.Context,
+	portfolioService services.PortfolioService,
+	userID string,
+) (domain.Portfolio, error) {
+	if c.Query("portfolioId") == "" {
+		portfolio, err := portfolioService.FindDefaultByUserID(userID)
+		if err != nil {
+			return domain.Portfolio{}, fmt.Errorf("failed to find default portfolio: %w", err)
+		}
+		return portfolio, nil
+	}
+
+	portfolio, err := portfolioService.FindByID(c.Query("portfolioId"))
+	if err != nil {
+		if err == domain.ErrPortfolioNotFound {
+			return domain.Portfolio{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.Portfolio{}, fmt.Errorf("failed to find portfolio: %w", err)
+	}
+
+	if portfolio.UserID != userID {
+		return domain.Portfolio{}, NewError(http.StatusForbidden, "not allowed to read portfolio")
+	}
+	return portfolio, nil
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/pointer"
+	xslices "growfolio/internal/slices"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type ReturnHandler struct {
+	investmentService services.InvestmentService
+	returnService     services.ReturnService
+	portfolioService  services.PortfolioService
+	tagService        services.TagService
+}
+
+func NewReturnHandler(
+	investmentService services.InvestmentService,
+	returnService services.ReturnService,
+	portfolioService services.PortfolioService,
+	tagService services.TagService,
+) ReturnHandler {
+	return ReturnHandler{
+		investmentService: investmentService,
+		returnService:     returnService,
+		portfolioService:  portfolioService,
+		tagService:        tagService,
+	}
+}
+
+func (h ReturnHandler) GetTimeWeightedReturns(c *gin.Context) (response[timeWeightedReturnsDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+	investmentIDFilter := pointer.StringOrNil(c.Query("investmentId"))
+
+	dateFrom, err := parseDateQuery(c, "dateFrom")
+	if err != nil {
+		return response[timeWeightedReturnsDto]{}, err
+	}
+	dateTo, err := parseDateQuery(c, "dateTo")
+	if err != nil {
+		return response[timeWeightedReturnsDto]{}, err
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[timeWeightedReturnsDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[timeWeightedReturnsDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	if investmentIDFilter != nil {
+		investmentIndex := slices.IndexFunc(investments, func(i domain.Investment) bool { return i.ID == *investmentIDFilter })
+		if investmentIndex == -1 {
+			return response[timeWeightedReturnsDto]{}, NewError(http.StatusForbidden, "not allowed to read investment returns")
+		}
+		investments = investments[investmentIndex : investmentIndex+1]
+	}
+
+	returns, err := h.returnService.FindTimeWeightedReturns(domain.FindReturnsQuery{
+		Investments: investments,
+		Currency:    portfolio.Currency,
+		DateFrom:    dateFrom,
+		DateTo:      dateTo,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[timeWeightedReturnsDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		return response[timeWeightedReturnsDto]{}, fmt.Errorf("failed to find time-weighted returns: %w", err)
+	}
+
+	investmentIDs := xslices.Map(investments, func(i domain.Investment) string { return i.ID })
+	return newResponse(http.StatusOK, toTimeWeightedReturnsDto(returns, investmentIDs)), nil
+}
+
+func (h ReturnHandler) GetInvestmentReturns(c *gin.Context) (response[returnsDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[returnsDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[returnsDto]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[returnsDto]{}, NewError(http.StatusForbidden, "not allowed to read investment returns")
+	}
+
+	return h.findReturns(c, []domain.Investment{investment}, investment.Currency)
+}
+
+func (h ReturnHandler) GetPortfolioReturns(c *gin.Context) (response[returnsDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[returnsDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[returnsDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	return h.findReturns(c, investments, portfolio.Currency)
+}
+
+// GetPortfolioReturnsByTag returns the returns of every tag group. The money-weighted return of a group is left out
+// when its cash flows have no XIRR, so one group does not fail the others.
+func (h ReturnHandler) GetPortfolioReturnsByTag(c *gin.Context) (response[[]tagReturnsDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	dateFrom, err := parseDateQuery(c, "dateFrom")
+	if err != nil {
+		return response[[]tagReturnsDto]{}, err
+	}
+	dateTo, err := parseDateQuery(c, "dateTo")
+	if err != nil {
+		return response[[]tagReturnsDto]{}, err
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]tagReturnsDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]tagReturnsDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	groups, err := h.tagService.GroupByTag(tokenUserID, investments)
+	if err != nil {
+		return response[[]tagReturnsDto]{}, fmt.Errorf("failed to group investments by tag: %w", err)
+	}
+
+	dtos := make([]tagReturnsDto, 0)
+	for _, group := range groups {
+		query := domain.FindReturnsQuery{
+			Investments: group.Investments,
+			Currency:    portfolio.Currency,
+			DateFrom:    dateFrom,
+			DateTo:      dateTo,
+		}
+
+		timeWeightedReturns, err := h.returnService.FindTimeWeightedReturns(query)
+		if err != nil {
+			if errors.Is(err, domain.ErrFXRateNotFound) {
+				return response[[]tagReturnsDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+			}
+			return response[[]tagReturnsDto]{}, fmt.Errorf("failed to find time-weighted returns: %w", err)
+		}
+
+		var moneyWeightedReturn *float64
+		returns, err := h.returnService.FindReturns(query)
+		if err == nil {
+			moneyWeightedReturn = &returns.MoneyWeightedReturn
+		} else if !errors.Is(err, domain.ErrCashFlowsWithoutSignChange) && !errors.Is(err, domain.ErrXIRRNotConverged) {
+			return response[[]tagReturnsDto]{}, fmt.Errorf("failed to find returns: %w", err)
+		}
+
+		dtos = append(dtos, newTagReturnsDto(toTagDtoOrNil(group.Tag), timeWeightedReturns.Portfolio, moneyWeightedReturn))
+	}
+
+	return newResponse(http.StatusOK, dtos), nil
+}
+
+func (h ReturnHandler) findReturns(
+	c *gin.Context,
+	investments []domain.Investment,
+	currency domain.Currency,
+) (response[returnsDto], error) {
+	dateFrom, err := parseDateQuery(c, "dateFrom")
+	if err != nil {
+		return response[returnsDto]{}, err
+	}
+	dateTo, err := parseDateQuery(c, "dateTo")
+	if err != nil {
+		return response[returnsDto]{}, err
+	}
+
+	returns, err := h.returnService.FindReturns(domain.FindReturnsQuery{
+		Investments: investments,
+		Currency:    currency,
+		DateFrom:    dateFrom,
+		DateTo:      dateTo,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[returnsDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		if errors.Is(err, domain.ErrCashFlowsWithoutSignChange) {
+			return response[returnsDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrCashFlowsWithoutSignChange.Error())
+		}
+		if errors.Is(err, domain.ErrXIRRNotConverged) {
+			return response[returnsDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrXIRRNotConverged.Error())
+		}
+		return response[returnsDto]{}, fmt.Errorf("failed to find returns: %w", err)
+	}
+
+	return newResponse(http.StatusOK, newReturnsDto(returns.TimeWeightedReturn, returns.MoneyWeightedReturn)), nil
+}
+
+func toTimeWeightedReturnsDto(r domain.TimeWeightedReturns, investmentIDs []string) timeWeightedReturnsDto {
+	investments := make([]investmentTimeWeightedReturnDto, 0)
+	for _, investmentID := range investmentIDs {
+		investments = append(investments, newInvestmentTimeWeightedReturnDto(investmentID, r.ByInvestmentID[investmentID]))
+	}
+	return newTimeWeightedReturnsDto(r.Portfolio, investments)
+}
+
+type timeWeightedReturnsDto struct {
+	Portfolio   float64                           `json:"portfolio"`
+	Investments []investmentTimeWeightedReturnDto `json:"investments"`
+}
+
+func newTimeWeightedReturnsDto(portfolio float64, investments []investmentTimeWeightedReturnDto) timeWeightedReturnsDto {
+	return timeWeightedReturnsDto{
+		Portfolio:   portfolio,
+		Investments: investments,
+	}
+}
+
+type investmentTimeWeightedReturnDto struct {
+	InvestmentID       string  `json:"investmentId"`
+	TimeWeightedReturn float64 `json:"timeWeightedReturn"`
+}
+
+func newInvestmentTimeWeightedReturnDto(investmentID string, timeWeightedReturn float64) investmentTimeWeightedReturnDto {
+	return investmentTimeWeightedReturnDto{
+		InvestmentID:       investmentID,
+		TimeWeightedReturn: timeWeightedReturn,
+	}
+}
+
+type returnsDto struct {
+	TimeWeightedReturn  float64 `json:"timeWeightedReturn"`
+	MoneyWeightedReturn float64 `json:"moneyWeightedReturn"`
+}
+
+func newReturnsDto(timeWeightedReturn, moneyWeightedReturn float64) returnsDto {
+	return returnsDto{
+		TimeWeightedReturn:  timeWeightedReturn,
+		MoneyWeightedReturn: moneyWeightedReturn,
+	}
+}
+
+type tagReturnsDto struct {
+	Tag                 *tagDto  `json:"tag"`
+	TimeWeightedReturn  float64  `json:"timeWeightedReturn"`
+	MoneyWeightedReturn *float64 `json:"moneyWeightedReturn"`
+}
+
+func newTagReturnsDto(tag *tagDto, timeWeightedReturn float64, moneyWeightedReturn *float64) tagReturnsDto {
+	return tagReturnsDto{
+		Tag:                 tag,
+		TimeWeightedReturn:  timeWeightedReturn,
+		MoneyWeightedReturn: moneyWeightedReturn,
+	}
+}
@@ -31,6 +31,13 @@ func (s *Server) RegisterRoutes(r *gin.Engine) {
 		public.POST("/demo-sessions", createHandlerFunc(s.handlers.demo.CreateDemoSession))
 	}
 
+	// the fx rates are shared by every user, so only an admin can import them
+	admin := r.Group("/admin")
+	admin.Use(s.middlewares.admin)
+	{
+		admin.POST("/fx-rates/csv", createHandlerFuncWithResponse(s.handlers.fxRate.ImportFXRates))
+	}
+
 	private := r.Group("")
 	private.Use(s.middlewares.token)
 	{
@@ -39,14 +46,72 @@ func (s *Server) RegisterRoutes(r *gin.Engine) {
 		private.GET("/investments", createHandlerFuncWithResponse(s.handlers.investment.GetInvestments))
 		private.POST("/investments", createHandlerFuncWithResponse(s.handlers.investment.CreateInvestment))
 		private.GET("/investments/:id", createHandlerFuncWithResponse(s.handlers.investment.GetInvestment))
+		private.PATCH("/investments/:id", createHandlerFuncWithResponse(s.handlers.investment.PatchInvestment))
 		private.DELETE("/investments/:id", createHandlerFuncWithResponse(s.handlers.investment.DeleteInvestment))
+		private.POST("/investments/:id/close", createHandlerFuncWithResponse(s.handlers.investment.CloseInvestment))
+		private.POST("/investments/:id/reopen", createHandlerFuncWithResponse(s.handlers.investment.ReopenInvestment))
 		private.POST("/investments/:id/updates", createHandlerFuncWithResponse(s.handlers.investment.CreateUpdate))
 		private.POST("/investments/:id/updates/csv", createHandlerFuncWithResponse(s.handlers.investment.ImportUpdates))
+		private.POST("/investments/:id/updates/statement", createHandlerFuncWithResponse(s.handlers.investment.ImportStatement))
 		private.GET("/investments/:id/updates/csv", createHandlerFunc(s.handlers.investment.ExportUpdates))
+		private.POST("/investments/:id/transactions", createHandlerFuncWithResponse(s.handlers.transaction.CreateTransaction))
+		private.GET("/investments/:id/holdings", createHandlerFuncWithResponse(s.handlers.investment.GetHoldings))
+		private.GET("/investments/:id/returns", createHandlerFuncWithResponse(s.handlers.returns.GetInvestmentReturns))
+		private.PUT("/investments/:id/tags", createHandlerFuncWithResponse(s.handlers.tag.UpdateInvestmentTags))
 
 		private.GET("/investment-updates", createHandlerFuncWithResponse(s.handlers.investmentUpdate.GetInvestmentUpdates))
+		private.POST("/investment-updates/batch", createHandlerFuncWithResponse(s.handlers.investmentUpdate.CreateInvestmentUpdates))
+		private.PUT("/investment-updates/:id", createHandlerFuncWithResponse(s.handlers.investmentUpdate.UpdateInvestmentUpdate))
+		private.PATCH("/investment-updates/:id", createHandlerFuncWithResponse(s.handlers.investmentUpdate.PatchInvestmentUpdate))
 		private.DELETE("/investment-updates/:id", createHandlerFuncWithResponse(s.handlers.investmentUpdate.DeleteInvestmentUpdate))
 
+		private.GET("/transactions", createHandlerFuncWithResponse(s.handlers.transaction.GetTransactions))
+		private.PUT("/transactions/:id", createHandlerFuncWithResponse(s.handlers.transaction.UpdateTransaction))
+		private.DELETE("/transactions/:id", createHandlerFuncWithResponse(s.handlers.transaction.DeleteTransaction))
+
+		private.GET("/time-weighted-returns", createHandlerFuncWithResponse(s.handlers.returns.GetTimeWeightedReturns))
+
+		private.GET("/portfolios", createHandlerFuncWithResponse(s.handlers.portfolio.GetPortfolios))
+		private.POST("/portfolios", createHandlerFuncWithResponse(s.handlers.portfolio.CreatePortfolio))
+		private.PATCH("/portfolios/:id", createHandlerFuncWithResponse(s.handlers.portfolio.PatchPortfolio))
+		private.DELETE("/portfolios/:id", createHandlerFuncWithResponse(s.handlers.portfolio.DeletePortfolio))
+
+		private.GET("/portfolio/returns", createHandlerFuncWithResponse(s.handlers.returns.GetPortfolioReturns))
+		private.GET("/portfolio/returns/by-tag", createHandlerFuncWithResponse(s.handlers.returns.GetPortfolioReturnsByTag))
+		private.GET("/portfolio/history", createHandlerFuncWithResponse(s.handlers.portfolio.GetHistory))
+		private.GET("/portfolio/history/by-tag", createHandlerFuncWithResponse(s.handlers.portfolio.GetHistoryByTag))
+		private.GET("/portfolio/allocation", createHandlerFuncWithResponse(s.handlers.portfolio.GetAllocation))
+		private.GET("/portfolio/allocation/by-tag", createHandlerFuncWithResponse(s.handlers.portfolio.GetAllocationByTag))
+		private.GET("/portfolio/gains", createHandlerFuncWithResponse(s.handlers.portfolio.GetGains))
+		private.GET("/portfolio/fees", createHandlerFuncWithResponse(s.handlers.portfolio.GetFees))
+		private.GET("/portfolio/income", createHandlerFuncWithResponse(s.handlers.portfolio.GetIncome))
+		private.GET("/portfolio/rebalance", createHandlerFuncWithResponse(s.handlers.targetAllocation.GetRebalance))
+
+		private.GET("/target-allocations", createHandlerFuncWithResponse(s.handlers.targetAllocation.GetTargetAllocations))
+		private.PUT("/target-allocations", createHandlerFuncWithResponse(s.handlers.targetAllocation.UpdateTargetAllocations))
+
+		private.GET("/tags", createHandlerFuncWithResponse(s.handlers.tag.GetTags))
+		private.POST("/tags", createHandlerFuncWithResponse(s.handlers.tag.CreateTag))
+		private.PATCH("/tags/:id", createHandlerFuncWithResponse(s.handlers.tag.UpdateTag))
+		private.DELETE("/tags/:id", createHandlerFuncWithResponse(s.handlers.tag.DeleteTag))
+
+		private.GET("/goals", createHandlerFuncWithResponse(s.handlers.goal.GetGoals))
+		private.POST("/goals", createHandlerFuncWithResponse(s.handlers.goal.CreateGoal))
+		private.GET("/goals/:id", createHandlerFuncWithResponse(s.handlers.goal.GetGoal))
+		private.PUT("/goals/:id", createHandlerFuncWithResponse(s.handlers.goal.UpdateGoal))
+		private.DELETE("/goals/:id", createHandlerFuncWithResponse(s.handlers.goal.DeleteGoal))
+
+		private.GET("/contribution-plans", createHandlerFuncWithResponse(s.handlers.contributionPlan.GetContributionPlans))
+		private.POST("/investments/:id/contribution-plans", createHandlerFuncWithResponse(s.handlers.contributionPlan.CreateContributionPlan))
+		private.PATCH("/contribution-plans/:id", createHandlerFuncWithResponse(s.handlers.contributionPlan.PatchContributionPlan))
+		private.POST("/contribution-plans/:id/skip", createHandlerFuncWithResponse(s.handlers.contributionPlan.SkipContributionPlan))
+		private.DELETE("/contribution-plans/:id", createHandlerFuncWithResponse(s.handlers.contributionPlan.DeleteContributionPlan))
+
+		private.GET("/import-profiles", createHandlerFuncWithResponse(s.handlers.importProfile.GetImportProfiles))
+		private.POST("/import-profiles", createHandlerFuncWithResponse(s.handlers.importProfile.CreateImportProfile))
+		private.PATCH("/import-profiles/:id", createHandlerFuncWithResponse(s.handlers.importProfile.UpdateImportProfile))
+		private.DELETE("/import-profiles/:id", createHandlerFuncWithResponse(s.handlers.importProfile.DeleteImportProfile))
+
 		private.GET("/user", createHandlerFuncWithResponse(s.handlers.user.GetUser))
 
 		private.GET("/settings", createHandlerFuncWithResponse(s.handlers.settings.GetSettings))
@@ -101,6 +101,15 @@ func createHandlerFunc(f func(c *gin.Context) error) gin.HandlerFunc {
 type Handlers struct {
 	investment       InvestmentHandler
 	investmentUpdate InvestmentUpdateHandler
+	transaction      TransactionHandler
+	returns          ReturnHandler
+	portfolio        PortfolioHandler
+	targetAllocation TargetAllocationHandler
+	tag              TagHandler
+	goal             GoalHandler
+	contributionPlan ContributionPlanHandler
+	importProfile    ImportProfileHandler
+	fxRate           FXRateHandler
 	auth             AuthHandler
 	user             UserHandler
 	settings         SettingsHandler
@@ -113,6 +122,15 @@ type Handlers struct {
 func NewHandlers(
 	investment InvestmentHandler,
 	investmentUpdate InvestmentUpdateHandler,
+	transaction TransactionHandler,
+	returns ReturnHandler,
+	portfolio PortfolioHandler,
+	targetAllocation TargetAllocationHandler,
+	tag TagHandler,
+	goal GoalHandler,
+	contributionPlan ContributionPlanHandler,
+	importProfile ImportProfileHandler,
+	fxRate FXRateHandler,
 	auth AuthHandler,
 	user UserHandler,
 	settings SettingsHandler,
@@ -124,6 +142,15 @@ func NewHandlers(
 	return Handlers{
 		investment:       investment,
 		investmentUpdate: investmentUpdate,
+		transaction:      transaction,
+		returns:          returns,
+		portfolio:        portfolio,
+		targetAllocation: targetAllocation,
+		tag:              tag,
+		goal:             goal,
+		contributionPlan: contributionPlan,
+		importProfile:    importProfile,
+		fxRate:           fxRate,
 		auth:             auth,
 		user:             user,
 		settings:         settings,
@@ -136,10 +163,12 @@ func NewHandlers(
 
 type Middlewares struct {
 	token gin.HandlerFunc
+	admin gin.HandlerFunc
 }
 
-func NewMiddlewares(token gin.HandlerFunc) Middlewares {
+func NewMiddlewares(token, admin gin.HandlerFunc) Middlewares {
 	return Middlewares{
 		token: token,
+		admin: admin,
 	}
 }
@@ -27,7 +27,7 @@ func (h SettingsHandler) GetSettings(c *gin.Context) (response[settingsDto], err
 		return response[settingsDto]{}, fmt.Errorf("failed to find settings by user id %s: %w", tokenUserID, err)
 	}
 
-	return newResponse(http.StatusOK, newSettingsDto(string(settings.Currency))), nil
+	return newResponse(http.StatusOK, toSettingsDto(settings)), nil
 }
 
 func (h SettingsHandler) UpdateSettings(c *gin.Context) (response[settingsDto], error) {
@@ -40,24 +40,50 @@ func (h SettingsHandler) UpdateSettings(c *gin.Context) (response[settingsDto],
 		return response[settingsDto]{}, fmt.Errorf("failed to decode request body: %w", err)
 	}
 
-	updated, err := h.settingsService.Update(domain.NewSettings(tokenUserID, domain.Currency(request.Currency)))
+	currency, err := domain.ParseCurrency(request.Currency)
+	if err != nil {
+		return response[settingsDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	settings, err := h.settingsService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[settingsDto]{}, fmt.Errorf("failed to find settings by user id %s: %w", tokenUserID, err)
+	}
+
+	staleAfterDays := settings.StaleAfterDays
+	if request.StaleAfterDays != nil {
+		if *request.StaleAfterDays <= 0 {
+			return response[settingsDto]{}, NewError(http.StatusBadRequest, "field 'staleAfterDays' must be positive")
+		}
+		staleAfterDays = *request.StaleAfterDays
+	}
+
+	updated, err := h.settingsService.Update(domain.NewSettings(tokenUserID, currency, staleAfterDays))
 	if err != nil {
 		return response[settingsDto]{}, fmt.Errorf("failed to update settings: %w", err)
 	}
 
-	return newResponse(http.StatusOK, newSettingsDto(string(updated.Currency))), nil
+	return newResponse(http.StatusOK, toSettingsDto(updated)), nil
+}
+
+func toSettingsDto(s domain.Settings) settingsDto {
+	return newSettingsDto(string(s.Currency), s.StaleAfterDays)
 }
 
 type settingsDto struct {
-	Currency string `json:"currency"`
+	Currency       string `json:"currency"`
+	StaleAfterDays int    `json:"staleAfterDays"`
 }
 
-func newSettingsDto(currency string) settingsDto {
+func newSettingsDto(currency string, staleAfterDays int) settingsDto {
 	return settingsDto{
-		Currency: currency,
+		Currency:       currency,
+		StaleAfterDays: staleAfterDays,
 	}
 }
 
+// updateSettingsRequest keeps the stale threshold of the user when it is left out.
 type updateSettingsRequest struct {
-	Currency string `json:"currency"`
+	Currency       string `json:"currency"`
+	StaleAfterDays *int   `json:"staleAfterDays"`
 }
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrUnknownStatementFormat = errors.New("unknown statement format")
+
+var ErrInvalidStatement = errors.New("statement is invalid")
+
+// StatementImporter reads the export of a broker into a statement. An importer wraps ErrInvalidStatement when the
+// export cannot be read or does not tell the currency of the account.
+type StatementImporter interface {
+	Parse(reader io.Reader) (domain.Statement, error)
+}
+
+// InvestmentUpdateStatementImporter imports the exports of brokers as updates of a single investment, which stands for
+// the whole account. The importers are keyed by the format that is given when importing.
+type InvestmentUpdateStatementImporter struct {
+	investmentUpdateService services.InvestmentUpdateService
+	statementImporters      map[string]StatementImporter
+}
+
+func NewInvestmentUpdateStatementImporter(
+	investmentUpdateService services.InvestmentUpdateService,
+	statementImporters map[string]StatementImporter,
+) InvestmentUpdateStatementImporter {
+	return InvestmentUpdateStatementImporter{
+		investmentUpdateService: investmentUpdateService,
+		statementImporters:      statementImporters,
+	}
+}
+
+func (s InvestmentUpdateStatementImporter) Formats() []string {
+	formats := make([]string, 0)
+	for format := range s.statementImporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Validate parses the statement into updates without writing anything.
+func (s InvestmentUpdateStatementImporter) Validate(
+	format string,
+	reader io.Reader,
+	investment domain.Investment,
+) ([]domain.CreateInvestmentUpdateCommand, error) {
+	statementImporter, ok := s.statementImporters[format]
+	if !ok {
+		return []domain.CreateInvestmentUpdateCommand{}, errors.Wrapf(ErrUnknownStatementFormat, "%q", format)
+	}
+
+	statement, err := statementImporter.Parse(reader)
+	if err != nil {
+		return []domain.CreateInvestmentUpdateCommand{}, err
+	}
+	if statement.IsEmpty() {
+		return []domain.CreateInvestmentUpdateCommand{}, errors.Wrap(ErrInvalidStatement, "no cash movements or valuations found")
+	}
+	if statement.Currency != investment.Currency {
+		return []domain.CreateInvestmentUpdateCommand{}, errors.Wrapf(
+			ErrInvalidStatement, "the account is in %s, but the investment is in %s", statement.Currency, investment.Currency,
+		)
+	}
+
+	return statement.ToCreateInvestmentUpdateCommands(investment), nil
+}
+
+// Import writes the updates of the statement with the merge strategy for dates that already have an update.
+func (s InvestmentUpdateStatementImporter) Import(
+	format string,
+	reader io.Reader,
+	investment domain.Investment,
+	strategy domain.MergeStrategy,
+) ([]domain.CreateInvestmentUpdateCommand, domain.ImportResult, error) {
+	commands, err := s.Validate(format, reader, investment)
+	if err != nil {
+		return []domain.CreateInvestmentUpdateCommand{}, domain.ImportResult{}, err
+	}
+
+	result, err := s.investmentUpdateService.Import(commands, strategy)
+	if err != nil {
+		return commands, domain.ImportResult{}, errors.Wrap(err, "failed to import updates")
+	}
+
+	return commands, result, nil
+}
+
+// statementPositions values an account from its trades for the statements without market prices. Every position is
+// valued at the price of its last trade in the statement, in the currency of the account.
+type statementPositions struct {
+	units  map[string]float64
+	prices map[string]float64
+}
+
+func newStatementPositions() statementPositions {
+	return statementPositions{
+		units:  make(map[string]float64),
+		prices: make(map[string]float64),
+	}
+}
+
+// trade adds the units to the position of the instrument. Units are negative for a sale.
+func (p statementPositions) trade(instrument string, units, price float64) {
+	p.units[instrument] += units
+	p.prices[instrument] = price
+}
+
+// value is in cents.
+func (p statementPositions) value() int64 {
+	var value float64
+	for instrument, units := range p.units {
+		value += units * p.prices[instrument]
+	}
+	return int64(math.Round(value * 100))
+}
+
+// parseStatementAmount reads an amount in units of a currency into cents.
+func parseStatementAmount(s string) (int64, error) {
+	parsed, err := parseStatementNumber(s)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(parsed * 100)), nil
+}
+
+// parseStatementNumber reads numbers like "1234.56", "1234,56", "1,234.56" and "1.234,56". With both a dot and a comma,
+// the last one is the decimal separator. With only one of them, it is the decimal separator unless it occurs more than
+// once, so "1,5" is 1.5 and "1.234.567" is 1234567.
+func parseStatementNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	dot := strings.LastIndex(s, ".")
+	comma := strings.LastIndex(s, ",")
+	switch {
+	case dot >= 0 && comma >= 0 && comma > dot:
+		s = strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), ",", ".")
+	case dot >= 0 && comma >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	case comma >= 0 && strings.Count(s, ",") > 1:
+		s = strings.ReplaceAll(s, ",", "")
+	case comma >= 0:
+		s = strings.ReplaceAll(s, ",", ".")
+	case strings.Count(s, ".") > 1:
+		s = strings.ReplaceAll(s, ".", "")
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"growfolio/internal/domain"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// statementDay is what a statement tells about a date: the net cash that was moved and the last valuation.
+type statementDay struct {
+	cash  int64
+	value int64
+}
+
+func statementDays(statement domain.Statement) map[string]statementDay {
+	days := make(map[string]statementDay)
+	for _, movement := range statement.CashMovements {
+		date := movement.Date.Format("2006-01-02")
+		day := days[date]
+		day.cash += movement.Amount
+		days[date] = day
+	}
+	for _, valuation := range statement.Valuations {
+		date := valuation.Date.Format("2006-01-02")
+		day := days[date]
+		day.value = valuation.Value
+		days[date] = day
+	}
+	return days
+}
+
+func cashMovements(statement domain.Statement) map[string]int64 {
+	movements := make(map[string]int64)
+	for _, movement := range statement.CashMovements {
+		movements[movement.Date.Format("2006-01-02")] += movement.Amount
+	}
+	return movements
+}
+
+type statementTest struct {
+	name          string
+	input         func(t *testing.T) string
+	currency      domain.Currency
+	cashMovements map[string]int64
+	days          map[string]statementDay
+	invalid       bool
+}
+
+func fixture(path string) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		content, err := os.ReadFile("../../demo/statements/" + path)
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		return string(content)
+	}
+}
+
+func inline(content string) func(t *testing.T) string {
+	return func(t *testing.T) string {
+		return content
+	}
+}
+
+func runStatementTests(t *testing.T, importer StatementImporter, tests []statementTest) {
+	t.Helper()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statement, err := importer.Parse(strings.NewReader(test.input(t)))
+			if test.invalid {
+				if !errors.Is(err, ErrInvalidStatement) {
+					t.Fatalf("expected ErrInvalidStatement, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if statement.Currency != test.currency {
+				t.Errorf("currency = %s, expected %s", statement.Currency, test.currency)
+			}
+			if got := cashMovements(statement); !reflect.DeepEqual(got, test.cashMovements) {
+				t.Errorf("cash movements = %v, expected %v", got, test.cashMovements)
+			}
+			if test.days != nil {
+				if got := statementDays(statement); !reflect.DeepEqual(got, test.days) {
+					t.Errorf("days = %v, expected %v", got, test.days)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStatementNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		invalid  bool
+	}{
+		{input: "1234.56", expected: 1234.56},
+		{input: "1234,56", expected: 1234.56},
+		{input: "1.234,56", expected: 1234.56},
+		{input: "1,234.56", expected: 1234.56},
+		{input: "-1.234,56", expected: -1234.56},
+		{input: " 200,00 ", expected: 200},
+		{input: "1.234.567", expected: 1234567},
+		{input: "1,234,567", expected: 1234567},
+		{input: "0.94096", expected: 0.94096},
+		{input: "", invalid: true},
+		{input: "abc", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			parsed, err := parseStatementNumber(test.input)
+			if test.invalid {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", parsed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if parsed != test.expected {
+				t.Errorf("parsed = %v, expected %v", parsed, test.expected)
+			}
+		})
+	}
+}
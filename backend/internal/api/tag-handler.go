@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/pointer"
+	"growfolio/internal/slices"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type TagHandler struct {
+	tagService        services.TagService
+	investmentService services.InvestmentService
+}
+
+func NewTagHandler(tagService services.TagService, investmentService services.InvestmentService) TagHandler {
+	return TagHandler{
+		tagService:        tagService,
+		investmentService: investmentService,
+	}
+}
+
+func (h TagHandler) GetTags(c *gin.Context) (response[[]tagDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	tags, err := h.tagService.FindByUserID(tokenUserID)
+	if err != nil {
+		return response[[]tagDto]{}, fmt.Errorf("failed to find tags: %w", err)
+	}
+
+	return newResponse(http.StatusOK, slices.Map(tags, toTagDto)), nil
+}
+
+func (h TagHandler) CreateTag(c *gin.Context) (response[tagDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveTagRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[tagDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	tag, err := h.tagService.Create(tokenUserID, request.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTag) {
+			return response[tagDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[tagDto]{}, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return newResponse(http.StatusCreated, toTagDto(tag)), nil
+}
+
+func (h TagHandler) UpdateTag(c *gin.Context) (response[tagDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveTagRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[tagDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	tag, err := h.findOwnedTag(c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[tagDto]{}, err
+	}
+
+	renamed, err := h.tagService.Rename(tag, request.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTag) {
+			return response[tagDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[tagDto]{}, fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toTagDto(renamed)), nil
+}
+
+func (h TagHandler) DeleteTag(c *gin.Context) (response[empty], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	tag, err := h.findOwnedTag(c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[empty]{}, err
+	}
+
+	err = h.tagService.DeleteByID(tag.ID)
+	if err != nil {
+		return response[empty]{}, fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return newEmptyResponse(http.StatusNoContent), nil
+}
+
+func (h TagHandler) UpdateInvestmentTags(c *gin.Context) (response[[]string], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var tagIDs []string
+	err := c.ShouldBindJSON(&tagIDs)
+	if err != nil {
+		return response[[]string]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[[]string]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[[]string]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[[]string]{}, NewError(http.StatusForbidden, "not allowed to update investment tags")
+	}
+
+	err = h.tagService.ReplaceInvestmentTags(investment, tagIDs)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTag) {
+			return response[[]string]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[[]string]{}, fmt.Errorf("failed to replace investment tags: %w", err)
+	}
+
+	updated, err := h.investmentService.FindByID(investment.ID)
+	if err != nil {
+		return response[[]string]{}, fmt.Errorf("failed to find investment by id %s: %w", id, err)
+	}
+
+	return newResponse(http.StatusOK, updated.TagIDs), nil
+}
+
+func (h TagHandler) findOwnedTag(id, userID string) (domain.Tag, error) {
+	tag, err := h.tagService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrTagNotFound {
+			return domain.Tag{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.Tag{}, fmt.Errorf("failed to find tag: %w", err)
+	}
+
+	if tag.UserID != userID {
+		return domain.Tag{}, NewError(http.StatusForbidden, "not allowed to modify tag")
+	}
+
+	return tag, nil
+}
+
+type saveTagRequest struct {
+	Name string `json:"name"`
+}
+
+func toTagDto(t domain.Tag) tagDto {
+	return newTagDto(t.ID, t.Name)
+}
+
+// toTagDtoOrNil maps the missing tag of the untagged group to null.
+func toTagDtoOrNil(t *domain.Tag) *tagDto {
+	if t == nil {
+		return nil
+	}
+	return pointer.Of(toTagDto(*t))
+}
+
+type tagDto struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newTagDto(id, name string) tagDto {
+	return tagDto{
+		ID:   id,
+		Name: name,
+	}
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/slices"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultRebalanceTolerance = 5.0
+
+type TargetAllocationHandler struct {
+	targetAllocationService services.TargetAllocationService
+	portfolioService        services.PortfolioService
+}
+
+func NewTargetAllocationHandler(
+	targetAllocationService services.TargetAllocationService,
+	portfolioService services.PortfolioService,
+) TargetAllocationHandler {
+	return TargetAllocationHandler{
+		targetAllocationService: targetAllocationService,
+		portfolioService:        portfolioService,
+	}
+}
+
+func (h TargetAllocationHandler) GetTargetAllocations(c *gin.Context) (response[[]targetAllocationDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]targetAllocationDto]{}, err
+	}
+
+	targets, err := h.targetAllocationService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]targetAllocationDto]{}, fmt.Errorf("failed to find target allocations: %w", err)
+	}
+
+	return newResponse(http.StatusOK, slices.Map(targets, toTargetAllocationDto)), nil
+}
+
+func (h TargetAllocationHandler) UpdateTargetAllocations(c *gin.Context) (response[[]targetAllocationDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request []updateTargetAllocationRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[[]targetAllocationDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]targetAllocationDto]{}, err
+	}
+
+	targets := slices.Map(request, func(r updateTargetAllocationRequest) domain.TargetAllocation {
+		return domain.NewTargetAllocation("", tokenUserID, portfolio.ID, r.InvestmentType, r.InvestmentID, r.Percentage)
+	})
+
+	updated, err := h.targetAllocationService.Replace(portfolio, targets)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTargetAllocations) {
+			return response[[]targetAllocationDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[[]targetAllocationDto]{}, fmt.Errorf("failed to replace target allocations: %w", err)
+	}
+
+	return newResponse(http.StatusOK, slices.Map(updated, toTargetAllocationDto)), nil
+}
+
+func (h TargetAllocationHandler) GetRebalance(c *gin.Context) (response[rebalanceDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	tolerance := defaultRebalanceTolerance
+	if c.Query("tolerance") != "" {
+		parsed, err := strconv.ParseFloat(c.Query("tolerance"), 64)
+		if err != nil || parsed < 0 {
+			return response[rebalanceDto]{}, NewError(http.StatusBadRequest, "invalid tolerance: "+c.Query("tolerance"))
+		}
+		tolerance = parsed
+	}
+
+	var newMoney *int64
+	if c.Query("newMoney") != "" {
+		parsed, err := strconv.ParseInt(c.Query("newMoney"), 10, 64)
+		if err != nil || parsed < 0 {
+			return response[rebalanceDto]{}, NewError(http.StatusBadRequest, "invalid newMoney: "+c.Query("newMoney"))
+		}
+		newMoney = &parsed
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[rebalanceDto]{}, err
+	}
+
+	rebalance, err := h.targetAllocationService.Rebalance(portfolio, tolerance, newMoney)
+	if err != nil {
+		if err == domain.ErrTargetAllocationsNotFound {
+			return response[rebalanceDto]{}, NewError(http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvalidTargetAllocations) {
+			return response[rebalanceDto]{}, NewError(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, domain.ErrFXRateNotFound) {
+			return response[rebalanceDto]{}, NewError(http.StatusUnprocessableEntity, domain.ErrFXRateNotFound.Error())
+		}
+		return response[rebalanceDto]{}, fmt.Errorf("failed to rebalance: %w", err)
+	}
+
+	return newResponse(http.StatusOK, toRebalanceDto(rebalance)), nil
+}
+
+func toTargetAllocationDto(t domain.TargetAllocation) targetAllocationDto {
+	return newTargetAllocationDto(t.ID, t.InvestmentType, t.InvestmentID, t.Percentage)
+}
+
+func toRebalanceDto(r domain.Rebalance) rebalanceDto {
+	entries := slices.Map(r.Entries, func(e domain.RebalanceEntry) rebalanceEntryDto {
+		return newRebalanceEntryDto(
+			e.InvestmentID,
+			e.CurrentValue,
+			e.CurrentPercentage,
+			e.TargetValue,
+			e.TargetPercentage,
+			e.WithinTolerance,
+			e.Amount,
+		)
+	})
+	return newRebalanceDto(r.Total, r.NewMoney, entries)
+}
+
+type updateTargetAllocationRequest struct {
+	InvestmentType *domain.InvestmentType `json:"investmentType"`
+	InvestmentID   *string                `json:"investmentId"`
+	Percentage     float64                `json:"percentage"`
+}
+
+type targetAllocationDto struct {
+	ID             string                 `json:"id"`
+	InvestmentType *domain.InvestmentType `json:"investmentType"`
+	InvestmentID   *string                `json:"investmentId"`
+	Percentage     float64                `json:"percentage"`
+}
+
+func newTargetAllocationDto(
+	id string,
+	investmentType *domain.InvestmentType,
+	investmentID *string,
+	percentage float64,
+) targetAllocationDto {
+	return targetAllocationDto{
+		ID:             id,
+		InvestmentType: investmentType,
+		InvestmentID:   investmentID,
+		Percentage:     percentage,
+	}
+}
+
+type rebalanceDto struct {
+	Total    int64               `json:"total"`
+	NewMoney int64               `json:"newMoney"`
+	Entries  []rebalanceEntryDto `json:"entries"`
+}
+
+func newRebalanceDto(total, newMoney int64, entries []rebalanceEntryDto) rebalanceDto {
+	return rebalanceDto{
+		Total:    total,
+		NewMoney: newMoney,
+		Entries:  entries,
+	}
+}
+
+type rebalanceEntryDto struct {
+	InvestmentID      string  `json:"investmentId"`
+	CurrentValue      int64   `json:"currentValue"`
+	CurrentPercentage float64 `json:"currentPercentage"`
+	TargetValue       int64   `json:"targetValue"`
+	TargetPercentage  float64 `json:"targetPercentage"`
+	WithinTolerance   bool    `json:"withinTolerance"`
+	Amount            int64   `json:"amount"`
+}
+
+func newRebalanceEntryDto(
+	investmentID string,
+	currentValue int64,
+	currentPercentage float64,
+	targetValue int64,
+	targetPercentage float64,
+	withinTolerance bool,
+	amount int64,
+) rebalanceEntryDto {
+	return rebalanceEntryDto{
+		InvestmentID:      investmentID,
+		CurrentValue:      currentValue,
+		CurrentPercentage: currentPercentage,
+		TargetValue:       targetValue,
+		TargetPercentage:  targetPercentage,
+		WithinTolerance:   withinTolerance,
+		Amount:            amount,
+	}
+}
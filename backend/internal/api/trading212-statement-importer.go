@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/csv"
+	"growfolio/internal/domain"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Trading212StatementImporter reads the history CSV of Trading 212. The totals are in the currency of the account and
+// the exchange rate is the price currency per unit of the account currency. The history has no market prices, so the
+// account is valued at the cash, which follows from the totals, plus the positions.
+type Trading212StatementImporter struct{}
+
+func NewTrading212StatementImporter() Trading212StatementImporter {
+	return Trading212StatementImporter{}
+}
+
+type trading212Row struct {
+	row    int
+	record []string
+}
+
+func (i Trading212StatementImporter) Parse(reader io.Reader) (domain.Statement, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	stringRecords, err := csvReader.ReadAll()
+	if err != nil {
+		return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to read CSV records: %s", err.Error())
+	}
+	if len(stringRecords) == 0 {
+		return domain.Statement{}, errors.Wrap(ErrInvalidStatement, "CSV file is empty")
+	}
+
+	// older exports name the total after the currency, like "Total (EUR)", instead of having a column for it
+	columns := make(map[string]int)
+	var currency string
+	for index, name := range stringRecords[0] {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if strings.HasPrefix(name, "Total (") {
+			currency = strings.TrimSuffix(strings.TrimPrefix(name, "Total ("), ")")
+			name = "Total"
+		}
+		columns[name] = index
+	}
+	for _, name := range []string{"Action", "Time", "Total"} {
+		if _, ok := columns[name]; !ok {
+			return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "column %q not found", name)
+		}
+	}
+
+	rows := make([]trading212Row, 0)
+	for index, stringRecord := range stringRecords[1:] {
+		rows = append(rows, trading212Row{row: index + 2, record: stringRecord})
+	}
+	// the times are written as 2006-01-02 15:04:05, so they sort as text
+	sort.SliceStable(rows, func(a, b int) bool {
+		return trading212Cell(rows[a].record, columns, "Time") < trading212Cell(rows[b].record, columns, "Time")
+	})
+
+	statement := domain.Statement{}
+	positions := newStatementPositions()
+	var cash int64
+	for _, row := range rows {
+		cell := func(name string) string {
+			return trading212Cell(row.record, columns, name)
+		}
+
+		if rowCurrency := cell("Currency (Total)"); rowCurrency != "" {
+			if currency != "" && rowCurrency != currency {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "total in %s on row %d, but in %s before", rowCurrency, row.row, currency)
+			}
+			currency = rowCurrency
+		}
+
+		date, err := time.Parse("2006-01-02", strings.SplitN(cell("Time"), " ", 2)[0])
+		if err != nil {
+			return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse time on row %d: %s", row.row, err.Error())
+		}
+		var total int64
+		if cell("Total") != "" {
+			total, err = parseStatementAmount(cell("Total"))
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse total on row %d: %s", row.row, err.Error())
+			}
+		}
+		// the sign of the total differs between exports, so the action decides the sign of cash movements and trades
+		amount := total
+		if amount < 0 {
+			amount = -amount
+		}
+
+		action := strings.ToLower(cell("Action"))
+		switch {
+		case action == "deposit":
+			cash += amount
+			statement.CashMovements = append(statement.CashMovements, domain.NewStatementCashMovement(date, amount))
+		case action == "withdrawal":
+			cash -= amount
+			statement.CashMovements = append(statement.CashMovements, domain.NewStatementCashMovement(date, -amount))
+		case trading212IsTrade(action):
+			shares, err := parseStatementNumber(cell("No. of shares"))
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse number of shares on row %d: %s", row.row, err.Error())
+			}
+			price, err := parseStatementNumber(cell("Price / share"))
+			if err != nil {
+				return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to parse price on row %d: %s", row.row, err.Error())
+			}
+			exchangeRate := 1.0
+			if cell("Exchange rate") != "" {
+				exchangeRate, err = parseStatementNumber(cell("Exchange rate"))
+				if err != nil || exchangeRate == 0 {
+					return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "invalid exchange rate on row %d", row.row)
+				}
+			}
+
+			instrument := cell("ISIN")
+			if instrument == "" {
+				instrument = cell("Ticker")
+			}
+
+			if strings.HasSuffix(action, " buy") {
+				cash -= amount
+				positions.trade(instrument, shares, price/exchangeRate)
+			} else {
+				cash += amount
+				positions.trade(instrument, -shares, price/exchangeRate)
+			}
+		default:
+			// dividends, interest and the like only change the cash
+			cash += total
+		}
+
+		statement.Valuations = append(statement.Valuations, domain.NewStatementValuation(date, cash+positions.value()))
+	}
+
+	statement.Currency, err = domain.ParseCurrency(currency)
+	if err != nil {
+		return domain.Statement{}, errors.Wrapf(ErrInvalidStatement, "failed to find the currency of the account: %s", err.Error())
+	}
+
+	return statement, nil
+}
+
+func trading212IsTrade(action string) bool {
+	return strings.HasSuffix(action, " buy") || strings.HasSuffix(action, " sell")
+}
+
+func trading212Cell(record []string, columns map[string]int, name string) string {
+	index, ok := columns[name]
+	if !ok || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
@@ -0,0 +1,62 @@
+package api
+
+import "testing"
+
+func TestTrading212StatementImporter_Parse(t *testing.T) {
+	runStatementTests(t, NewTrading212StatementImporter(), []statementTest{
+		{
+			name:     "history CSV",
+			input:    fixture("trading212-history.csv"),
+			currency: "EUR",
+			cashMovements: map[string]int64{
+				"2023-03-01": 100000,
+				"2023-03-20": -20000,
+				"2023-04-01": 25000,
+			},
+			days: map[string]statementDay{
+				"2023-03-01": {cash: 100000, value: 100000},
+				"2023-03-02": {value: 99950},
+				"2023-03-05": {value: 99968},
+				"2023-03-10": {value: 100005},
+				"2023-03-15": {value: 98830},
+				"2023-03-20": {cash: -20000, value: 78830},
+				"2023-04-01": {cash: 25000, value: 103830},
+			},
+		},
+		{
+			// older exports write the withdrawals and the buys with a positive total
+			name: "older export with positive totals and the currency in the header",
+			input: inline("Action,Time,ISIN,Ticker,No. of shares,Price / share,Exchange rate,Total (GBP)\n" +
+				"Withdrawal,2023-03-03 10:00:00,,,,,,50.00\n" +
+				"Market buy,2023-03-02 09:00:00,GB00B03MLX29,RDSA,2,25.00,1.00,50.00\n" +
+				"Deposit,2023-03-01 09:00:00,,,,,,200.00\n"),
+			currency: "GBP",
+			cashMovements: map[string]int64{
+				"2023-03-01": 20000,
+				"2023-03-03": -5000,
+			},
+			days: map[string]statementDay{
+				"2023-03-01": {cash: 20000, value: 20000},
+				"2023-03-02": {value: 20000},
+				"2023-03-03": {cash: -5000, value: 15000},
+			},
+		},
+		{
+			name: "totals in more than one currency",
+			input: inline("Action,Time,Total,Currency (Total)\n" +
+				"Deposit,2023-03-01 09:00:00,200.00,EUR\n" +
+				"Deposit,2023-03-02 09:00:00,200.00,USD\n"),
+			invalid: true,
+		},
+		{
+			name:    "no currency",
+			input:   inline("Action,Time,Total\nDeposit,2023-03-01 09:00:00,200.00\n"),
+			invalid: true,
+		},
+		{
+			name:    "no column Total",
+			input:   inline("Action,Time\nDeposit,2023-03-01 09:00:00\n"),
+			invalid: true,
+		},
+	})
+}
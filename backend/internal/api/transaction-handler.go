@@ -0,0 +1,266 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/domain/services"
+	"growfolio/internal/pointer"
+	xslices "growfolio/internal/slices"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type TransactionHandler struct {
+	investmentService  services.InvestmentService
+	transactionService services.TransactionService
+	portfolioService   services.PortfolioService
+}
+
+func NewTransactionHandler(
+	investmentService services.InvestmentService,
+	transactionService services.TransactionService,
+	portfolioService services.PortfolioService,
+) TransactionHandler {
+	return TransactionHandler{
+		investmentService:  investmentService,
+		transactionService: transactionService,
+		portfolioService:   portfolioService,
+	}
+}
+
+func (h TransactionHandler) GetTransactions(c *gin.Context) (response[[]transactionDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+	investmentIDFilter := pointer.StringOrNil(c.Query("investmentId"))
+
+	dateFrom, err := parseDateQuery(c, "dateFrom")
+	if err != nil {
+		return response[[]transactionDto]{}, err
+	}
+	dateTo, err := parseDateQuery(c, "dateTo")
+	if err != nil {
+		return response[[]transactionDto]{}, err
+	}
+
+	var types []domain.TransactionType
+	if c.Query("type") != "" {
+		for _, t := range strings.Split(c.Query("type"), ",") {
+			transactionType := domain.TransactionType(t)
+			if !transactionType.IsValid() {
+				return response[[]transactionDto]{}, NewError(http.StatusBadRequest, "invalid type: "+t)
+			}
+			types = append(types, transactionType)
+		}
+	}
+
+	portfolio, err := findPortfolioQuery(c, h.portfolioService, tokenUserID)
+	if err != nil {
+		return response[[]transactionDto]{}, err
+	}
+
+	investments, err := h.investmentService.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return response[[]transactionDto]{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+	if len(investments) == 0 {
+		return newResponse(http.StatusOK, []transactionDto{}), nil
+	}
+
+	investmentIDs := xslices.Map(investments, func(i domain.Investment) string { return i.ID })
+	if investmentIDFilter != nil {
+		if !slices.Contains(investmentIDs, *investmentIDFilter) {
+			return response[[]transactionDto]{}, NewError(http.StatusForbidden, "not allowed to read transactions")
+		}
+		investmentIDs = []string{*investmentIDFilter}
+	}
+
+	transactions, err := h.transactionService.Find(domain.FindTransactionQuery{
+		InvestmentIDs: investmentIDs,
+		Types:         types,
+		DateFrom:      dateFrom,
+		DateTo:        dateTo,
+	})
+	if err != nil {
+		return response[[]transactionDto]{}, fmt.Errorf("failed to find transactions: %w", err)
+	}
+
+	return newResponse(http.StatusOK, xslices.Map(transactions, toTransactionDto)), nil
+}
+
+func (h TransactionHandler) CreateTransaction(c *gin.Context) (response[transactionDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveTransactionRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[transactionDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	id := c.Param("id")
+	investment, err := h.investmentService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return response[transactionDto]{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return response[transactionDto]{}, fmt.Errorf("failed to find investment: %w", err)
+	}
+
+	if investment.UserID != tokenUserID {
+		return response[transactionDto]{}, NewError(http.StatusForbidden, "not allowed to create transaction for investment")
+	}
+
+	command, err := request.toCommand(investment)
+	if err != nil {
+		return response[transactionDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	transaction, err := h.transactionService.Create(command)
+	if err != nil {
+		return response[transactionDto]{}, h.toSaveError(err, "failed to create transaction")
+	}
+
+	return newResponse(http.StatusCreated, toTransactionDto(transaction)), nil
+}
+
+func (h TransactionHandler) UpdateTransaction(c *gin.Context) (response[transactionDto], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	var request saveTransactionRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		return response[transactionDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	transaction, investment, err := h.findOwnedTransaction(c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[transactionDto]{}, err
+	}
+
+	command, err := request.toCommand(investment)
+	if err != nil {
+		return response[transactionDto]{}, NewError(http.StatusBadRequest, err.Error())
+	}
+
+	updated, err := h.transactionService.Update(transaction.ID, command)
+	if err != nil {
+		return response[transactionDto]{}, h.toSaveError(err, "failed to update transaction")
+	}
+
+	return newResponse(http.StatusOK, toTransactionDto(updated)), nil
+}
+
+func (h TransactionHandler) DeleteTransaction(c *gin.Context) (response[empty], error) {
+	tokenClaims := c.Value("token").(*jwt.Token).Claims.(jwt.MapClaims)
+	tokenUserID := tokenClaims["userId"].(string)
+
+	transaction, investment, err := h.findOwnedTransaction(c.Param("id"), tokenUserID)
+	if err != nil {
+		return response[empty]{}, err
+	}
+	if investment.Locked {
+		return response[empty]{}, NewError(http.StatusForbidden, domain.ErrInvestmentIsLocked.Error())
+	}
+
+	err = h.transactionService.DeleteByID(transaction.ID)
+	if err != nil {
+		return response[empty]{}, fmt.Errorf("failed to delete transaction: %w", err)
+	}
+
+	return newEmptyResponse(http.StatusNoContent), nil
+}
+
+func (h TransactionHandler) findOwnedTransaction(id, userID string) (domain.Transaction, domain.Investment, error) {
+	transaction, err := h.transactionService.FindByID(id)
+	if err != nil {
+		if err == domain.ErrTransactionNotFound {
+			return domain.Transaction{}, domain.Investment{}, NewError(http.StatusNotFound, err.Error())
+		}
+		return domain.Transaction{}, domain.Investment{}, fmt.Errorf("failed to find transaction: %w", err)
+	}
+
+	investment, err := h.investmentService.FindByID(transaction.InvestmentID)
+	if err != nil {
+		if err == domain.ErrInvestmentNotFound {
+			return domain.Transaction{}, domain.Investment{}, NewError(http.StatusBadRequest, err.Error())
+		}
+		return domain.Transaction{}, domain.Investment{}, fmt.Errorf("failed to find investment: %w", err)
+	}
+
+	if investment.UserID != userID {
+		return domain.Transaction{}, domain.Investment{}, NewError(http.StatusForbidden, "not allowed to modify transaction")
+	}
+
+	return transaction, investment, nil
+}
+
+func (h TransactionHandler) toSaveError(err error, message string) error {
+	if err == domain.ErrInvestmentIsLocked {
+		return NewError(http.StatusForbidden, err.Error())
+	}
+	if err == domain.ErrInvestmentIsClosed {
+		return NewError(http.StatusConflict, err.Error())
+	}
+	if errors.Is(err, domain.ErrInvalidTransaction) {
+		return NewError(http.StatusBadRequest, err.Error())
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+type saveTransactionRequest struct {
+	Date   string                 `json:"date"`
+	Type   domain.TransactionType `json:"type"`
+	Amount int64                  `json:"amount"`
+	Units  *float64               `json:"units"`
+	Note   *string                `json:"note"`
+}
+
+func (r saveTransactionRequest) toCommand(investment domain.Investment) (domain.SaveTransactionCommand, error) {
+	date, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return domain.SaveTransactionCommand{}, fmt.Errorf("failed to parse date: %w", err)
+	}
+
+	return domain.NewSaveTransactionCommand(investment, date, r.Type, r.Amount, r.Units, r.Note), nil
+}
+
+func toTransactionDto(t domain.Transaction) transactionDto {
+	return newTransactionDto(t.ID, t.InvestmentID, t.Date.Format("2006-01-02"), t.Type, t.Amount, t.Units, t.Note)
+}
+
+type transactionDto struct {
+	ID           string                 `json:"id"`
+	InvestmentID string                 `json:"investmentId"`
+	Date         string                 `json:"date"`
+	Type         domain.TransactionType `json:"type"`
+	Amount       int64                  `json:"amount"`
+	Units        *float64               `json:"units"`
+	Note         *string                `json:"note"`
+}
+
+func newTransactionDto(
+	id,
+	investmentID,
+	date string,
+	t domain.TransactionType,
+	amount int64,
+	units *float64,
+	note *string,
+) transactionDto {
+	return transactionDto{
+		ID:           id,
+		InvestmentID: investmentID,
+		Date:         date,
+		Type:         t,
+		Amount:       amount,
+		Units:        units,
+		Note:         note,
+	}
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type ContributionFrequency string
+
+const (
+	ContributionFrequencyMonthly   ContributionFrequency = "monthly"
+	ContributionFrequencyQuarterly ContributionFrequency = "quarterly"
+	ContributionFrequencyYearly    ContributionFrequency = "yearly"
+)
+
+func (f ContributionFrequency) IsValid() bool {
+	switch f {
+	case ContributionFrequencyMonthly, ContributionFrequencyQuarterly, ContributionFrequencyYearly:
+		return true
+	}
+	return false
+}
+
+// Months is the number of months between two runs.
+func (f ContributionFrequency) Months() int {
+	switch f {
+	case ContributionFrequencyQuarterly:
+		return 3
+	case ContributionFrequencyYearly:
+		return 12
+	}
+	return 1
+}
+
+// ContributionPlan deposits a fixed amount into an investment on a day of the month. In months that are shorter than
+// the day of month, the plan runs on the last day of the month. A skipped month is stored as the first day of that
+// month and a run that falls in it does not deposit anything.
+type ContributionPlan struct {
+	ID            string
+	InvestmentID  string
+	Amount        int64
+	Frequency     ContributionFrequency
+	DayOfMonth    int
+	NextRunDate   time.Time
+	Paused        bool
+	SkippedMonths []time.Time
+}
+
+func NewContributionPlan(
+	id,
+	investmentID string,
+	amount int64,
+	frequency ContributionFrequency,
+	dayOfMonth int,
+	nextRunDate time.Time,
+	paused bool,
+	skippedMonths []time.Time,
+) ContributionPlan {
+	return ContributionPlan{
+		ID:            id,
+		InvestmentID:  investmentID,
+		Amount:        amount,
+		Frequency:     frequency,
+		DayOfMonth:    dayOfMonth,
+		NextRunDate:   nextRunDate,
+		Paused:        paused,
+		SkippedMonths: skippedMonths,
+	}
+}
+
+// IsSkipped tells whether the run on the given date falls in a skipped month.
+func (p ContributionPlan) IsSkipped(date time.Time) bool {
+	return slices.ContainsFunc(p.SkippedMonths, func(month time.Time) bool {
+		return month.Year() == date.Year() && month.Month() == date.Month()
+	})
+}
+
+// FollowingRunDate is the date of the run after the next run.
+func (p ContributionPlan) FollowingRunDate() time.Time {
+	month := time.Date(p.NextRunDate.Year(), p.NextRunDate.Month()+time.Month(p.Frequency.Months()), 1, 0, 0, 0, 0, time.UTC)
+	return RunDateInMonth(p.DayOfMonth, month)
+}
+
+// FirstRunDateOnOrAfter is the first date on or after the given date on which a plan with the day of month runs.
+func FirstRunDateOnOrAfter(dayOfMonth int, date time.Time) time.Time {
+	runDate := RunDateInMonth(dayOfMonth, date)
+	if runDate.Before(date) {
+		runDate = RunDateInMonth(dayOfMonth, time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, time.UTC))
+	}
+	return runDate
+}
+
+// RunDateInMonth is the day of month in the month of the given date, or the last day of that month when it is shorter.
+func RunDateInMonth(dayOfMonth int, month time.Time) time.Time {
+	lastDay := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(month.Year(), month.Month(), min(dayOfMonth, lastDay), 0, 0, 0, 0, time.UTC)
+}
+
+// SaveContributionPlanCommand starts the plan on the first run on or after the start date.
+type SaveContributionPlanCommand struct {
+	Amount     int64
+	Frequency  ContributionFrequency
+	DayOfMonth int
+	StartDate  time.Time
+}
+
+func NewSaveContributionPlanCommand(
+	amount int64,
+	frequency ContributionFrequency,
+	dayOfMonth int,
+	startDate time.Time,
+) SaveContributionPlanCommand {
+	return SaveContributionPlanCommand{
+		Amount:     amount,
+		Frequency:  frequency,
+		DayOfMonth: dayOfMonth,
+		StartDate:  startDate,
+	}
+}
+
+// UpdateContributionPlanCommand only changes the fields that are set.
+type UpdateContributionPlanCommand struct {
+	Amount     *int64
+	Frequency  *ContributionFrequency
+	DayOfMonth *int
+	Paused     *bool
+}
@@ -1,8 +1,36 @@
 package domain
 
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// Currency is an ISO 4217 currency code.
 type Currency string
 
 const (
-	CurrencyEuro     Currency = "EUR"
-	CurrencyUSDollar Currency = "USD"
+	CurrencyEuro             Currency = "EUR"
+	CurrencyUSDollar         Currency = "USD"
+	CurrencyBritishPound     Currency = "GBP"
+	CurrencySwissFranc       Currency = "CHF"
+	CurrencyJapaneseYen      Currency = "JPY"
+	CurrencyCanadianDollar   Currency = "CAD"
+	CurrencyAustralianDollar Currency = "AUD"
+	CurrencySwedishKrona     Currency = "SEK"
+	CurrencyNorwegianKrone   Currency = "NOK"
+	CurrencyDanishKrone      Currency = "DKK"
+	CurrencyPolishZloty      Currency = "PLN"
 )
+
+// FXRateBaseCurrency is the currency that all stored FX rates are quoted against, like the ECB reference rates.
+const FXRateBaseCurrency = CurrencyEuro
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func ParseCurrency(s string) (Currency, error) {
+	if !currencyPattern.MatchString(s) {
+		return "", errors.Wrapf(ErrInvalidCurrency, "%q is not a three-letter currency code", s)
+	}
+	return Currency(s), nil
+}
@@ -6,6 +6,8 @@ var ErrInvestmentNotFound = errors.New("investment not found")
 
 var ErrInvestmentUpdateNotFound = errors.New("investment update not found")
 
+var ErrInvestmentUpdateExists = errors.New("investment already has an update on this date")
+
 var ErrUserNotFound = errors.New("user not found")
 
 var ErrSettingsNotFound = errors.New("settings not found")
@@ -13,3 +15,45 @@ var ErrSettingsNotFound = errors.New("settings not found")
 var ErrMaxInvestmentsReached = errors.New("max investments reached")
 
 var ErrInvestmentIsLocked = errors.New("investment is locked")
+
+var ErrCashFlowsWithoutSignChange = errors.New("cash flows need at least one deposit and one withdrawal or value")
+
+var ErrXIRRNotConverged = errors.New("XIRR did not converge")
+
+var ErrInvalidTargetAllocations = errors.New("invalid target allocations")
+
+var ErrTargetAllocationsNotFound = errors.New("target allocations not found")
+
+var ErrInvalidCurrency = errors.New("invalid currency")
+
+var ErrFXRateNotFound = errors.New("fx rate not found")
+
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+var ErrInvalidInvestment = errors.New("invalid investment")
+
+var ErrInvestmentIsClosed = errors.New("investment is closed")
+
+var ErrTagNotFound = errors.New("tag not found")
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+var ErrPortfolioNotFound = errors.New("portfolio not found")
+
+var ErrInvalidPortfolio = errors.New("invalid portfolio")
+
+var ErrTooManyHistoryPoints = errors.New("too many history points")
+
+var ErrGoalNotFound = errors.New("goal not found")
+
+var ErrInvalidGoal = errors.New("invalid goal")
+
+var ErrContributionPlanNotFound = errors.New("contribution plan not found")
+
+var ErrInvalidContributionPlan = errors.New("invalid contribution plan")
+
+var ErrImportProfileNotFound = errors.New("import profile not found")
+
+var ErrInvalidImportProfile = errors.New("invalid import profile")
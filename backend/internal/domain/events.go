@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type UserCreatedEvent struct {
 	User User
 }
@@ -9,3 +11,49 @@ func NewUserCreatedEvent(user User) UserCreatedEvent {
 		User: user,
 	}
 }
+
+type InvestmentRenamedEvent struct {
+	Investment Investment
+	OldName    string
+}
+
+func NewInvestmentRenamedEvent(investment Investment, oldName string) InvestmentRenamedEvent {
+	return InvestmentRenamedEvent{
+		Investment: investment,
+		OldName:    oldName,
+	}
+}
+
+// InvestmentStaleEvent reminds the user to update an investment that has not been updated since before its stale
+// threshold.
+type InvestmentStaleEvent struct {
+	Investment Investment
+	StaleSince time.Time
+}
+
+func NewInvestmentStaleEvent(investment Investment, staleSince time.Time) InvestmentStaleEvent {
+	return InvestmentStaleEvent{
+		Investment: investment,
+		StaleSince: staleSince,
+	}
+}
+
+type GoalReachedEvent struct {
+	Progress GoalProgress
+}
+
+func NewGoalReachedEvent(progress GoalProgress) GoalReachedEvent {
+	return GoalReachedEvent{
+		Progress: progress,
+	}
+}
+
+type GoalBehindScheduleEvent struct {
+	Progress GoalProgress
+}
+
+func NewGoalBehindScheduleEvent(progress GoalProgress) GoalBehindScheduleEvent {
+	return GoalBehindScheduleEvent{
+		Progress: progress,
+	}
+}
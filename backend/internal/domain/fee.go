@@ -0,0 +1,39 @@
+package domain
+
+// InvestmentFees sums the fees of an investment. The recurring fees are estimated from the annual fee percentage and
+// the value between updates. The drag is how much the time-weighted return would have been higher without the fees.
+// Taxes from the ledger are reported next to the fees, but are not part of them.
+type InvestmentFees struct {
+	InvestmentID     string
+	OneOffFees       int64
+	RecurringFees    int64
+	Taxes            int64
+	ReturnBeforeFees float64
+	ReturnAfterFees  float64
+}
+
+func NewInvestmentFees(
+	investmentID string,
+	oneOffFees,
+	recurringFees,
+	taxes int64,
+	returnBeforeFees,
+	returnAfterFees float64,
+) InvestmentFees {
+	return InvestmentFees{
+		InvestmentID:     investmentID,
+		OneOffFees:       oneOffFees,
+		RecurringFees:    recurringFees,
+		Taxes:            taxes,
+		ReturnBeforeFees: returnBeforeFees,
+		ReturnAfterFees:  returnAfterFees,
+	}
+}
+
+func (f InvestmentFees) Total() int64 {
+	return f.OneOffFees + f.RecurringFees
+}
+
+func (f InvestmentFees) Drag() float64 {
+	return f.ReturnBeforeFees - f.ReturnAfterFees
+}
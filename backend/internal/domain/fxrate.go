@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FXRate is the amount of Currency that one unit of FXRateBaseCurrency buys on Date.
+type FXRate struct {
+	Date     time.Time
+	Currency Currency
+	Rate     float64
+}
+
+func NewFXRate(date time.Time, currency Currency, rate float64) FXRate {
+	return FXRate{
+		Date:     date,
+		Currency: currency,
+		Rate:     rate,
+	}
+}
+
+// FXRates converts amounts between currencies using the last known rate on or before a date.
+type FXRates struct {
+	ratesByCurrency map[Currency][]FXRate
+}
+
+func NewFXRates(rates []FXRate) FXRates {
+	ratesByCurrency := make(map[Currency][]FXRate)
+	for _, rate := range rates {
+		ratesByCurrency[rate.Currency] = append(ratesByCurrency[rate.Currency], rate)
+	}
+	for _, currencyRates := range ratesByCurrency {
+		sort.Slice(currencyRates, func(a, b int) bool { return currencyRates[a].Date.Before(currencyRates[b].Date) })
+	}
+
+	return FXRates{ratesByCurrency: ratesByCurrency}
+}
+
+func (r FXRates) Convert(amount int64, from, to Currency, date time.Time) (int64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, err := r.rate(from, date)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := r.rate(to, date)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(float64(amount) / fromRate * toRate)), nil
+}
+
+func (r FXRates) rate(currency Currency, date time.Time) (float64, error) {
+	if currency == FXRateBaseCurrency {
+		return 1, nil
+	}
+
+	rates := r.ratesByCurrency[currency]
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].Date.After(date) })
+	if i == 0 {
+		return 0, errors.Wrapf(ErrFXRateNotFound, "no %s rate on or before %s", currency, date.Format("2006-01-02"))
+	}
+	return rates[i-1].Rate, nil
+}
@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// GainAttributionMethod decides which part of a withdrawal is returned principal. The proportional method withdraws
+// principal and gain in the ratio in which they make up the value, the cost-first method returns all principal before
+// any gain is realized.
+type GainAttributionMethod string
+
+const (
+	GainAttributionMethodProportional GainAttributionMethod = "proportional"
+	GainAttributionMethodCostFirst    GainAttributionMethod = "costFirst"
+)
+
+func (m GainAttributionMethod) IsValid() bool {
+	switch m {
+	case GainAttributionMethodProportional, GainAttributionMethodCostFirst:
+		return true
+	}
+	return false
+}
+
+// Gains splits the result of an investment into the realized gain, which was taken out through withdrawals and
+// income, and the unrealized gain, which is the last value minus the principal that is still invested.
+type Gains struct {
+	InvestmentID string
+	Realized     int64
+	Unrealized   int64
+	Withdrawals  []AttributedWithdrawal
+	ByYear       []YearlyGains
+}
+
+func NewGains(
+	investmentID string,
+	realized,
+	unrealized int64,
+	withdrawals []AttributedWithdrawal,
+	byYear []YearlyGains,
+) Gains {
+	return Gains{
+		InvestmentID: investmentID,
+		Realized:     realized,
+		Unrealized:   unrealized,
+		Withdrawals:  withdrawals,
+		ByYear:       byYear,
+	}
+}
+
+type AttributedWithdrawal struct {
+	Date      time.Time
+	Amount    int64
+	Principal int64
+	Gain      int64
+}
+
+func NewAttributedWithdrawal(date time.Time, amount, principal, gain int64) AttributedWithdrawal {
+	return AttributedWithdrawal{
+		Date:      date,
+		Amount:    amount,
+		Principal: principal,
+		Gain:      gain,
+	}
+}
+
+// YearlyGains holds the gain realized during the year and the unrealized gain at the last update of the year.
+type YearlyGains struct {
+	Year       int
+	Realized   int64
+	Unrealized int64
+}
+
+func NewYearlyGains(year int, realized, unrealized int64) YearlyGains {
+	return YearlyGains{
+		Year:       year,
+		Realized:   realized,
+		Unrealized: unrealized,
+	}
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type GoalStatus string
+
+const (
+	GoalStatusOnTrack        GoalStatus = "onTrack"
+	GoalStatusBehindSchedule GoalStatus = "behindSchedule"
+	GoalStatusReached        GoalStatus = "reached"
+)
+
+// Goal is a target amount that a user wants to have saved by the target date. The investments of the goal and the
+// investments with any of its tags count toward it. The expected return is a percentage per year, which is assumed
+// for the projections. The status is the outcome of the last check of the goal and is nil before the first check.
+type Goal struct {
+	ID                       string
+	UserID                   string
+	Name                     string
+	TargetAmount             int64
+	TargetDate               time.Time
+	Currency                 Currency
+	ExpectedReturnPercentage float64
+	InvestmentIDs            []string
+	TagIDs                   []string
+	Status                   *GoalStatus
+}
+
+func NewGoal(
+	id,
+	userID,
+	name string,
+	targetAmount int64,
+	targetDate time.Time,
+	currency Currency,
+	expectedReturnPercentage float64,
+	investmentIDs,
+	tagIDs []string,
+	status *GoalStatus,
+) Goal {
+	return Goal{
+		ID:                       id,
+		UserID:                   userID,
+		Name:                     name,
+		TargetAmount:             targetAmount,
+		TargetDate:               targetDate,
+		Currency:                 currency,
+		ExpectedReturnPercentage: expectedReturnPercentage,
+		InvestmentIDs:            investmentIDs,
+		TagIDs:                   tagIDs,
+		Status:                   status,
+	}
+}
+
+// CountsToward tells whether the investment is part of the goal, either directly or through one of its tags.
+func (g Goal) CountsToward(investment Investment) bool {
+	if slices.Contains(g.InvestmentIDs, investment.ID) {
+		return true
+	}
+	for _, tagID := range g.TagIDs {
+		if investment.HasTag(tagID) {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveGoalCommand takes the currency of the settings of the user when no currency is given.
+type SaveGoalCommand struct {
+	Name                     string
+	TargetAmount             int64
+	TargetDate               time.Time
+	Currency                 Currency
+	ExpectedReturnPercentage float64
+	InvestmentIDs            []string
+	TagIDs                   []string
+}
+
+func NewSaveGoalCommand(
+	name string,
+	targetAmount int64,
+	targetDate time.Time,
+	currency Currency,
+	expectedReturnPercentage float64,
+	investmentIDs,
+	tagIDs []string,
+) SaveGoalCommand {
+	return SaveGoalCommand{
+		Name:                     name,
+		TargetAmount:             targetAmount,
+		TargetDate:               targetDate,
+		Currency:                 currency,
+		ExpectedReturnPercentage: expectedReturnPercentage,
+		InvestmentIDs:            investmentIDs,
+		TagIDs:                   tagIDs,
+	}
+}
+
+// GoalProgress holds the current value of the investments of a goal in the currency of the goal. The monthly
+// contribution is the average net deposit per month over the last year. The required monthly contribution is what
+// needs to be deposited every month from now on to reach the target on the target date at the expected return. The
+// projected completion date assumes that the monthly contribution continues and is nil when the target is never
+// reached.
+type GoalProgress struct {
+	Goal                        Goal
+	CurrentValue                int64
+	Percentage                  float64
+	MonthlyContribution         int64
+	RequiredMonthlyContribution int64
+	ProjectedCompletionDate     *time.Time
+	Status                      GoalStatus
+}
+
+func NewGoalProgress(
+	goal Goal,
+	currentValue int64,
+	percentage float64,
+	monthlyContribution,
+	requiredMonthlyContribution int64,
+	projectedCompletionDate *time.Time,
+	status GoalStatus,
+) GoalProgress {
+	return GoalProgress{
+		Goal:                        goal,
+		CurrentValue:                currentValue,
+		Percentage:                  percentage,
+		MonthlyContribution:         monthlyContribution,
+		RequiredMonthlyContribution: requiredMonthlyContribution,
+		ProjectedCompletionDate:     projectedCompletionDate,
+		Status:                      status,
+	}
+}
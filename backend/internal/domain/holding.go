@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Holding is the unit position of an investment after an update. Prices and costs are in the smallest currency unit.
+type Holding struct {
+	Date                  time.Time
+	Units                 float64
+	Price                 float64
+	CostBasis             int64
+	AverageCost           float64
+	UnrealizedGain        int64
+	UnrealizedGainPerUnit float64
+}
+
+func NewHolding(
+	date time.Time,
+	units,
+	price float64,
+	costBasis int64,
+	averageCost float64,
+	unrealizedGain int64,
+	unrealizedGainPerUnit float64,
+) Holding {
+	return Holding{
+		Date:                  date,
+		Units:                 units,
+		Price:                 price,
+		CostBasis:             costBasis,
+		AverageCost:           averageCost,
+		UnrealizedGain:        unrealizedGain,
+		UnrealizedGainPerUnit: unrealizedGainPerUnit,
+	}
+}
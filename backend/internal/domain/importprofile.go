@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AmountScale string
+
+const (
+	AmountScaleCents AmountScale = "cents"
+	AmountScaleUnits AmountScale = "units"
+)
+
+func (s AmountScale) IsValid() bool {
+	return s == AmountScaleCents || s == AmountScaleUnits
+}
+
+const (
+	ImportFieldDate       = "date"
+	ImportFieldDeposit    = "deposit"
+	ImportFieldWithdrawal = "withdrawal"
+	ImportFieldValue      = "value"
+	ImportFieldUnits      = "units"
+	ImportFieldPrice      = "price"
+	ImportFieldIncome     = "income"
+	ImportFieldFee        = "fee"
+)
+
+// ImportFields are the fields of an imported update in the order of the columns of a file without a column mapping.
+var ImportFields = []string{
+	ImportFieldDate,
+	ImportFieldDeposit,
+	ImportFieldWithdrawal,
+	ImportFieldValue,
+	ImportFieldUnits,
+	ImportFieldPrice,
+	ImportFieldIncome,
+	ImportFieldFee,
+}
+
+// dateFormatReplacer turns a date format like dd-mm-yyyy into the layout of the time package.
+var dateFormatReplacer = strings.NewReplacer("yyyy", "2006", "yy", "06", "mm", "01", "dd", "02")
+
+// ImportProfile describes how to read the CSV files of a bank or broker. The date format is written with yyyy, yy, mm
+// and dd, and an empty thousands separator means that amounts have none. The column mapping maps fields to the headers
+// of the file. Without a column mapping, the columns are read in the order of ImportFields. Amounts are in cents or in
+// units of the currency, while units and prices are always read as they are.
+type ImportProfile struct {
+	ID                 string
+	UserID             string
+	Name               string
+	Delimiter          string
+	DateFormat         string
+	DecimalSeparator   string
+	ThousandsSeparator string
+	ColumnMapping      map[string]string
+	AmountScale        AmountScale
+}
+
+func NewImportProfile(
+	id,
+	userID,
+	name,
+	delimiter,
+	dateFormat,
+	decimalSeparator,
+	thousandsSeparator string,
+	columnMapping map[string]string,
+	amountScale AmountScale,
+) ImportProfile {
+	return ImportProfile{
+		ID:                 id,
+		UserID:             userID,
+		Name:               name,
+		Delimiter:          delimiter,
+		DateFormat:         dateFormat,
+		DecimalSeparator:   decimalSeparator,
+		ThousandsSeparator: thousandsSeparator,
+		ColumnMapping:      columnMapping,
+		AmountScale:        amountScale,
+	}
+}
+
+// DefaultImportProfile reads the files that are exported by Growfolio itself.
+func DefaultImportProfile() ImportProfile {
+	return NewImportProfile("", "", "Default", ",", "yyyy-mm-dd", ".", "", map[string]string{}, AmountScaleCents)
+}
+
+// DateLayout is the date format as a layout of the time package.
+func (p ImportProfile) DateLayout() string {
+	return dateFormatReplacer.Replace(strings.ToLower(p.DateFormat))
+}
+
+func (p ImportProfile) ParseDate(s string) (time.Time, error) {
+	return time.Parse(p.DateLayout(), strings.TrimSpace(s))
+}
+
+// ParseAmount returns the amount in cents. Amounts in units are rounded to the nearest cent.
+func (p ImportProfile) ParseAmount(s string) (int64, error) {
+	normalized := p.normalizeNumber(s)
+	if p.AmountScale == AmountScaleUnits {
+		parsed, err := strconv.ParseFloat(normalized, 64)
+		if err != nil {
+			return 0, err
+		}
+		return int64(math.Round(parsed * 100)), nil
+	}
+	return strconv.ParseInt(normalized, 10, 64)
+}
+
+func (p ImportProfile) ParseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(p.normalizeNumber(s), 64)
+}
+
+func (p ImportProfile) normalizeNumber(s string) string {
+	s = strings.TrimSpace(s)
+	if p.ThousandsSeparator != "" {
+		s = strings.ReplaceAll(s, p.ThousandsSeparator, "")
+	}
+	if p.DecimalSeparator != "." {
+		s = strings.ReplaceAll(s, p.DecimalSeparator, ".")
+	}
+	return s
+}
+
+type SaveImportProfileCommand struct {
+	Name               string
+	Delimiter          string
+	DateFormat         string
+	DecimalSeparator   string
+	ThousandsSeparator string
+	ColumnMapping      map[string]string
+	AmountScale        AmountScale
+}
+
+func NewSaveImportProfileCommand(
+	name,
+	delimiter,
+	dateFormat,
+	decimalSeparator,
+	thousandsSeparator string,
+	columnMapping map[string]string,
+	amountScale AmountScale,
+) SaveImportProfileCommand {
+	return SaveImportProfileCommand{
+		Name:               name,
+		Delimiter:          delimiter,
+		DateFormat:         dateFormat,
+		DecimalSeparator:   decimalSeparator,
+		ThousandsSeparator: thousandsSeparator,
+		ColumnMapping:      columnMapping,
+		AmountScale:        amountScale,
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+type FindIncomeQuery struct {
+	Investments []Investment
+	Currency    Currency
+	DateFrom    *time.Time
+	DateTo      *time.Time
+}
+
+// Income sums the income that was paid out by the investments, in total, per month, per year and per investment. A
+// period is dated at its first day and only periods with income are included.
+type Income struct {
+	Total        int64
+	ByMonth      []IncomePeriod
+	ByYear       []IncomePeriod
+	ByInvestment []InvestmentIncome
+}
+
+func NewIncome(total int64, byMonth, byYear []IncomePeriod, byInvestment []InvestmentIncome) Income {
+	return Income{
+		Total:        total,
+		ByMonth:      byMonth,
+		ByYear:       byYear,
+		ByInvestment: byInvestment,
+	}
+}
+
+type IncomePeriod struct {
+	Date   time.Time
+	Amount int64
+}
+
+func NewIncomePeriod(date time.Time, amount int64) IncomePeriod {
+	return IncomePeriod{
+		Date:   date,
+		Amount: amount,
+	}
+}
+
+type InvestmentIncome struct {
+	InvestmentID string
+	Amount       int64
+}
+
+func NewInvestmentIncome(investmentID string, amount int64) InvestmentIncome {
+	return InvestmentIncome{
+		InvestmentID: investmentID,
+		Amount:       amount,
+	}
+}
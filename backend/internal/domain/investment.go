@@ -16,27 +16,47 @@ const (
 	InvestmentTypeForex      InvestmentType = "forex"
 )
 
+func (t InvestmentType) IsValid() bool {
+	switch t {
+	case InvestmentTypeStock, InvestmentTypeBond, InvestmentTypeCommodity, InvestmentTypeFund, InvestmentTypeCrypto,
+		InvestmentTypeCash, InvestmentTypeP2PLending, InvestmentTypeRealEstate, InvestmentTypeForex:
+		return true
+	}
+	return false
+}
+
+// CreateInvestmentCommand optionally holds a recurring fee, such as the TER of a fund or a custody fee, as a
+// percentage of the value per year. Without a currency, the investment takes the currency of its portfolio.
 type CreateInvestmentCommand struct {
-	Type          InvestmentType
-	Name          string
-	User          User
-	Locked        bool
-	InitialUpdate *InitialInvestmentUpdate
+	Type                InvestmentType
+	Name                string
+	Currency            Currency
+	AnnualFeePercentage *float64
+	User                User
+	Portfolio           Portfolio
+	Locked              bool
+	InitialUpdate       *InitialInvestmentUpdate
 }
 
 func NewCreateInvestmentCommand(
 	t InvestmentType,
 	name string,
+	currency Currency,
+	annualFeePercentage *float64,
 	user User,
+	portfolio Portfolio,
 	locked bool,
 	initialUpdate *InitialInvestmentUpdate,
 ) CreateInvestmentCommand {
 	return CreateInvestmentCommand{
-		Type:          t,
-		Name:          name,
-		User:          user,
-		Locked:        locked,
-		InitialUpdate: initialUpdate,
+		Type:                t,
+		Name:                name,
+		Currency:            currency,
+		AnnualFeePercentage: annualFeePercentage,
+		User:                user,
+		Portfolio:           portfolio,
+		Locked:              locked,
+		InitialUpdate:       initialUpdate,
 	}
 }
 
@@ -54,29 +74,113 @@ func NewInitialInvestmentUpdate(date *time.Time, deposit *int64, value int64) In
 	}
 }
 
+// Investment has a display order, which starts at 0 and decides the order in which the investments of a portfolio
+// are listed. A closed investment is no longer active, but its updates still count toward the history of the portfolio.
+// Without its own stale threshold, an investment is stale after the threshold in the settings of the user.
 type Investment struct {
-	ID         string
-	Type       InvestmentType
-	Name       string
-	UserID     string
-	Locked     bool
-	LastUpdate *InvestmentUpdate
+	ID                  string
+	Type                InvestmentType
+	Name                string
+	Currency            Currency
+	AnnualFeePercentage *float64
+	UserID              string
+	PortfolioID         string
+	Locked              bool
+	DisplayOrder        int
+	ClosedAt            *time.Time
+	StaleAfterDays      *int
+	StaleRemindedAt     *time.Time
+	TagIDs              []string
+	LastUpdate          *InvestmentUpdate
 }
 
 func NewInvestment(
 	id string,
 	t InvestmentType,
-	name,
+	name string,
+	currency Currency,
+	annualFeePercentage *float64,
 	userID string,
+	portfolioID string,
 	locked bool,
+	displayOrder int,
+	closedAt *time.Time,
+	staleAfterDays *int,
+	staleRemindedAt *time.Time,
+	tagIDs []string,
 	lastUpdate *InvestmentUpdate,
 ) Investment {
 	return Investment{
-		ID:         id,
-		Type:       t,
-		Name:       name,
-		UserID:     userID,
-		Locked:     locked,
-		LastUpdate: lastUpdate,
+		ID:                  id,
+		Type:                t,
+		Name:                name,
+		Currency:            currency,
+		AnnualFeePercentage: annualFeePercentage,
+		UserID:              userID,
+		PortfolioID:         portfolioID,
+		Locked:              locked,
+		DisplayOrder:        displayOrder,
+		ClosedAt:            closedAt,
+		StaleAfterDays:      staleAfterDays,
+		StaleRemindedAt:     staleRemindedAt,
+		TagIDs:              tagIDs,
+		LastUpdate:          lastUpdate,
+	}
+}
+
+func (i Investment) IsClosed() bool {
+	return i.ClosedAt != nil
+}
+
+// StaleSince is the date from which the investment is stale, which is the date of its last update plus the stale
+// threshold. Closed investments and investments without updates are never stale.
+func (i Investment) StaleSince(defaultStaleAfterDays int) *time.Time {
+	if i.IsClosed() || i.LastUpdate == nil {
+		return nil
+	}
+
+	staleAfterDays := defaultStaleAfterDays
+	if i.StaleAfterDays != nil {
+		staleAfterDays = *i.StaleAfterDays
+	}
+
+	staleSince := i.LastUpdate.Date.AddDate(0, 0, staleAfterDays)
+	return &staleSince
+}
+
+func (i Investment) IsStale(date time.Time, defaultStaleAfterDays int) bool {
+	staleSince := i.StaleSince(defaultStaleAfterDays)
+	return staleSince != nil && !staleSince.After(date)
+}
+
+func (i Investment) HasTag(tagID string) bool {
+	for _, id := range i.TagIDs {
+		if id == tagID {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateInvestmentCommand only changes the fields that are set. A new display order moves the investment to that
+// position and shifts the other investments of the user. A stale threshold of 0 falls back to the threshold of the user.
+type UpdateInvestmentCommand struct {
+	Type           *InvestmentType
+	Name           *string
+	DisplayOrder   *int
+	StaleAfterDays *int
+}
+
+func NewUpdateInvestmentCommand(
+	t *InvestmentType,
+	name *string,
+	displayOrder *int,
+	staleAfterDays *int,
+) UpdateInvestmentCommand {
+	return UpdateInvestmentCommand{
+		Type:           t,
+		Name:           name,
+		DisplayOrder:   displayOrder,
+		StaleAfterDays: staleAfterDays,
 	}
 }
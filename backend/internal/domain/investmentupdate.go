@@ -2,47 +2,125 @@ package domain
 
 import "time"
 
+// CreateInvestmentUpdateCommand optionally holds the number of units held after the update and the price per unit in
+// the smallest currency unit. When both are given, the value is derived from them. Income is paid out of the
+// investment, like a withdrawal, but counts as return instead of reducing the cost. A fee is a one-off cost that was
+// charged within the investment and is already reflected in its value.
 type CreateInvestmentUpdateCommand struct {
 	Investment Investment
 	Date       time.Time
 	Deposit    *int64
 	Withdrawal *int64
+	Income     *int64
+	Fee        *int64
 	Value      int64
+	Units      *float64
+	Price      *float64
 }
 
-func NewCreateInvestmentUpdateCommand(investment Investment, date time.Time, deposit, withdrawal *int64, value int64) CreateInvestmentUpdateCommand {
+func NewCreateInvestmentUpdateCommand(
+	investment Investment,
+	date time.Time,
+	deposit,
+	withdrawal,
+	income,
+	fee *int64,
+	value int64,
+	units,
+	price *float64,
+) CreateInvestmentUpdateCommand {
 	return CreateInvestmentUpdateCommand{
 		Investment: investment,
 		Date:       date,
 		Deposit:    deposit,
 		Withdrawal: withdrawal,
+		Income:     income,
+		Fee:        fee,
 		Value:      value,
+		Units:      units,
+		Price:      price,
 	}
 }
 
+// InvestmentUpdate holds the cost, which sums the deposits minus the withdrawals up to and including the update, and
+// the fees, which sum the one-off fees over the same updates. An update without a fee of its own counts the fees in the
+// ledger since the previous update instead.
 type InvestmentUpdate struct {
 	ID           string
 	InvestmentID string
 	Date         time.Time
 	Deposit      *int64
 	Withdrawal   *int64
+	Income       *int64
+	Fee          *int64
 	Cost         int64
+	Fees         int64
 	Value        int64
+	Units        *float64
+	Price        *float64
 }
 
-func NewInvestmentUpdate(id, investmentID string, date time.Time, deposit, withdrawal *int64, cost, value int64) InvestmentUpdate {
+func NewInvestmentUpdate(
+	id,
+	investmentID string,
+	date time.Time,
+	deposit,
+	withdrawal,
+	income,
+	fee *int64,
+	cost,
+	fees,
+	value int64,
+	units,
+	price *float64,
+) InvestmentUpdate {
 	return InvestmentUpdate{
 		ID:           id,
 		InvestmentID: investmentID,
 		Date:         date,
 		Deposit:      deposit,
 		Withdrawal:   withdrawal,
+		Income:       income,
+		Fee:          fee,
 		Cost:         cost,
+		Fees:         fees,
 		Value:        value,
+		Units:        units,
+		Price:        price,
 	}
 }
 
+// MergeStrategy decides what an import does with an update on a date that already has one.
+type MergeStrategy string
+
+const (
+	MergeStrategySkip      MergeStrategy = "skip"
+	MergeStrategyOverwrite MergeStrategy = "overwrite"
+	MergeStrategyFail      MergeStrategy = "fail"
+)
+
+func (s MergeStrategy) IsValid() bool {
+	switch s {
+	case MergeStrategySkip, MergeStrategyOverwrite, MergeStrategyFail:
+		return true
+	}
+	return false
+}
+
+type ImportResult struct {
+	Inserted int
+	Updated  int
+	Skipped  int
+}
+
 type FindInvestmentUpdateQuery struct {
 	InvestmentIDs []string
 	DateFrom      *time.Time
+	DateTo        *time.Time
+}
+
+// CostExcludingFees leaves the one-off fees out of the cost basis, including the fees in the ledger, which matches the
+// one-off fees of the fee report up to the update.
+func (u InvestmentUpdate) CostExcludingFees() int64 {
+	return u.Cost - u.Fees
 }
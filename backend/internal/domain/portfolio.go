@@ -0,0 +1,110 @@
+package domain
+
+import "time"
+
+const DefaultPortfolioName = "Default"
+
+// Portfolio groups the investments of a user. Each portfolio has its own reporting currency, in which its history,
+// allocation and returns are shown. Every user has at least one portfolio.
+type Portfolio struct {
+	ID       string
+	UserID   string
+	Name     string
+	Currency Currency
+}
+
+func NewPortfolio(id, userID, name string, currency Currency) Portfolio {
+	return Portfolio{
+		ID:       id,
+		UserID:   userID,
+		Name:     name,
+		Currency: currency,
+	}
+}
+
+// UpdatePortfolioCommand only changes the fields that are set.
+type UpdatePortfolioCommand struct {
+	Name     *string
+	Currency *Currency
+}
+
+type Interval string
+
+const (
+	IntervalDay     Interval = "day"
+	IntervalWeek    Interval = "week"
+	IntervalMonth   Interval = "month"
+	IntervalQuarter Interval = "quarter"
+	IntervalYear    Interval = "year"
+)
+
+// MaxHistoryPoints limits the number of intervals in a history, which is more than 13 years of days.
+const MaxHistoryPoints = 5000
+
+func (i Interval) IsValid() bool {
+	switch i {
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalQuarter, IntervalYear:
+		return true
+	}
+	return false
+}
+
+// FindPortfolioHistoryQuery leaves the one-off fees out of the cost when ExcludeFeesFromCost is set.
+type FindPortfolioHistoryQuery struct {
+	Investments         []Investment
+	Currency            Currency
+	Interval            Interval
+	DateFrom            *time.Time
+	DateTo              *time.Time
+	ExcludeFeesFromCost bool
+}
+
+type PortfolioHistoryPoint struct {
+	Date  time.Time
+	Cost  int64
+	Value int64
+}
+
+func NewPortfolioHistoryPoint(date time.Time, cost, value int64) PortfolioHistoryPoint {
+	return PortfolioHistoryPoint{
+		Date:  date,
+		Cost:  cost,
+		Value: value,
+	}
+}
+
+type FindAllocationHistoryQuery struct {
+	Investments []Investment
+	Currency    Currency
+	Interval    Interval
+	DateFrom    *time.Time
+	DateTo      *time.Time
+}
+
+type Allocation struct {
+	AsOf    time.Time
+	Total   int64
+	Entries []AllocationEntry
+}
+
+func NewAllocation(asOf time.Time, total int64, entries []AllocationEntry) Allocation {
+	return Allocation{
+		AsOf:    asOf,
+		Total:   total,
+		Entries: entries,
+	}
+}
+
+type AllocationEntry struct {
+	Type       InvestmentType
+	Value      int64
+	Percentage float64
+}
+
+func NewAllocationEntry(t InvestmentType, value int64, percentage float64) AllocationEntry {
+	return AllocationEntry{
+		Type:       t,
+		Value:      value,
+		Percentage: percentage,
+	}
+}
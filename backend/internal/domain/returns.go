@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+type FindReturnsQuery struct {
+	Investments []Investment
+	Currency    Currency
+	DateFrom    *time.Time
+	DateTo      *time.Time
+}
+
+type TimeWeightedReturns struct {
+	Portfolio      float64
+	ByInvestmentID map[string]float64
+}
+
+func NewTimeWeightedReturns(portfolio float64, byInvestmentID map[string]float64) TimeWeightedReturns {
+	return TimeWeightedReturns{
+		Portfolio:      portfolio,
+		ByInvestmentID: byInvestmentID,
+	}
+}
+
+type Returns struct {
+	TimeWeightedReturn  float64
+	MoneyWeightedReturn float64
+}
+
+func NewReturns(timeWeightedReturn, moneyWeightedReturn float64) Returns {
+	return Returns{
+		TimeWeightedReturn:  timeWeightedReturn,
+		MoneyWeightedReturn: moneyWeightedReturn,
+	}
+}
+
+// CashFlow is seen from the investor: money put in is negative, money taken out is positive.
+type CashFlow struct {
+	Date   time.Time
+	Amount int64
+}
+
+func NewCashFlow(date time.Time, amount int64) CashFlow {
+	return CashFlow{
+		Date:   date,
+		Amount: amount,
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+type ContributionPlanRunner struct {
+	contributionPlanService ContributionPlanService
+}
+
+func NewContributionPlanRunner(
+	contributionPlanService ContributionPlanService,
+) ContributionPlanRunner {
+	return ContributionPlanRunner{
+		contributionPlanService: contributionPlanService,
+	}
+}
+
+func (r ContributionPlanRunner) Run() {
+	slog.Info("Running contribution plans...")
+
+	date := today()
+	plans, err := r.contributionPlanService.FindDue(date)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to find due contribution plans: %+v", err))
+		return
+	}
+
+	for _, plan := range plans {
+		_, err := r.contributionPlanService.Run(plan, date)
+		if err != nil {
+			slog.Error(fmt.Sprintf("failed to run contribution plan %s: %+v", plan.ID, err))
+		}
+	}
+}
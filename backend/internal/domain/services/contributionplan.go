@@ -0,0 +1,233 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type ContributionPlanRepository interface {
+	FindByID(id string) (domain.ContributionPlan, error)
+	FindByInvestmentIDs(investmentIDs []string) ([]domain.ContributionPlan, error)
+	FindDue(date time.Time) ([]domain.ContributionPlan, error)
+
+	Create(plan domain.ContributionPlan) (domain.ContributionPlan, error)
+	Update(plan domain.ContributionPlan) (domain.ContributionPlan, error)
+	DeleteByID(id string) error
+}
+
+type ContributionPlanService struct {
+	contributionPlanRepository ContributionPlanRepository
+	investmentService          InvestmentService
+	unitOfWork                 UnitOfWork
+}
+
+func NewContributionPlanService(
+	contributionPlanRepository ContributionPlanRepository,
+	investmentService InvestmentService,
+	unitOfWork UnitOfWork,
+) ContributionPlanService {
+	return ContributionPlanService{
+		contributionPlanRepository: contributionPlanRepository,
+		investmentService:          investmentService,
+		unitOfWork:                 unitOfWork,
+	}
+}
+
+func (s ContributionPlanService) FindByID(id string) (domain.ContributionPlan, error) {
+	return s.contributionPlanRepository.FindByID(id)
+}
+
+func (s ContributionPlanService) FindByInvestmentIDs(investmentIDs []string) ([]domain.ContributionPlan, error) {
+	return s.contributionPlanRepository.FindByInvestmentIDs(investmentIDs)
+}
+
+func (s ContributionPlanService) FindDue(date time.Time) ([]domain.ContributionPlan, error) {
+	return s.contributionPlanRepository.FindDue(date)
+}
+
+// Create starts the plan on the first run on or after the start date, or today when no start date is given.
+func (s ContributionPlanService) Create(
+	investment domain.Investment,
+	command domain.SaveContributionPlanCommand,
+) (domain.ContributionPlan, error) {
+	if investment.IsClosed() {
+		return domain.ContributionPlan{}, domain.ErrInvestmentIsClosed
+	}
+
+	startDate := command.StartDate
+	if startDate.IsZero() {
+		startDate = today()
+	}
+
+	plan := domain.NewContributionPlan(
+		"",
+		investment.ID,
+		command.Amount,
+		command.Frequency,
+		command.DayOfMonth,
+		domain.FirstRunDateOnOrAfter(command.DayOfMonth, startDate),
+		false,
+		[]time.Time{},
+	)
+
+	err := s.validate(plan)
+	if err != nil {
+		return domain.ContributionPlan{}, err
+	}
+
+	return s.contributionPlanRepository.Create(plan)
+}
+
+// Update changes the fields of the command that are set. A plan that is resumed, or whose day of month changes, runs
+// next on the first matching date from today, so the runs missed while paused are not caught up.
+func (s ContributionPlanService) Update(
+	plan domain.ContributionPlan,
+	command domain.UpdateContributionPlanCommand,
+) (domain.ContributionPlan, error) {
+	reschedule := false
+
+	if command.Amount != nil {
+		plan.Amount = *command.Amount
+	}
+	if command.Frequency != nil {
+		plan.Frequency = *command.Frequency
+	}
+	if command.DayOfMonth != nil && *command.DayOfMonth != plan.DayOfMonth {
+		plan.DayOfMonth = *command.DayOfMonth
+		reschedule = true
+	}
+	if command.Paused != nil && *command.Paused != plan.Paused {
+		plan.Paused = *command.Paused
+		reschedule = !plan.Paused
+	}
+
+	err := s.validate(plan)
+	if err != nil {
+		return domain.ContributionPlan{}, err
+	}
+
+	if reschedule {
+		plan.NextRunDate = domain.FirstRunDateOnOrAfter(plan.DayOfMonth, today())
+	}
+
+	return s.contributionPlanRepository.Update(plan)
+}
+
+// Skip skips the run of the plan in the month of the given date.
+func (s ContributionPlanService) Skip(plan domain.ContributionPlan, month time.Time) (domain.ContributionPlan, error) {
+	if plan.IsSkipped(month) {
+		return plan, nil
+	}
+
+	plan.SkippedMonths = append(plan.SkippedMonths, time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC))
+	return s.contributionPlanRepository.Update(plan)
+}
+
+func (s ContributionPlanService) DeleteByID(id string) error {
+	return s.contributionPlanRepository.DeleteByID(id)
+}
+
+// Run makes every run of the plan that is due on or before the given date, so runs that were missed while the
+// scheduler was down are caught up. A run in a skipped month, or of an investment that is locked or closed, does not
+// deposit anything but still moves the plan to its following run.
+func (s ContributionPlanService) Run(plan domain.ContributionPlan, date time.Time) (domain.ContributionPlan, error) {
+	investment, err := s.investmentService.FindByID(plan.InvestmentID)
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to find investment: %w", err)
+	}
+
+	// every run deposits and moves the plan in a single unit of work, so a run is neither lost nor deposited twice
+	for !plan.Paused && !plan.NextRunDate.After(date) {
+		err := s.unitOfWork.Do(func(repositories Repositories) error {
+			if !plan.IsSkipped(plan.NextRunDate) && !investment.Locked && !investment.IsClosed() {
+				err := deposit(repositories, investment, plan.NextRunDate, plan.Amount)
+				if err != nil {
+					return errors.Wrapf(err, "failed to deposit on %s", plan.NextRunDate.Format("2006-01-02"))
+				}
+			}
+
+			next := plan
+			next.NextRunDate = plan.FollowingRunDate()
+			updated, err := repositories.ContributionPlan.Update(next)
+			if err != nil {
+				return fmt.Errorf("failed to update contribution plan: %w", err)
+			}
+			plan = updated
+			return nil
+		})
+		if err != nil {
+			return domain.ContributionPlan{}, err
+		}
+	}
+
+	return plan, nil
+}
+
+// deposit records a deposit-only update that carries the last value forward plus the deposit. When the investment
+// already has an update on the date, the deposit is added to that update instead. With a price, the deposit buys units
+// at that price. Without one, the units of that update no longer match its value, so they are left out.
+func deposit(repositories Repositories, investment domain.Investment, date time.Time, amount int64) error {
+	lastUpdate, err := repositories.InvestmentUpdate.FindLastByInvestmentIDAndDateLessThanEqual(investment.ID, date)
+	if err != nil {
+		if err != domain.ErrInvestmentUpdateNotFound {
+			return fmt.Errorf("failed to find last update: %w", err)
+		}
+
+		_, err := repositories.InvestmentUpdate.Create(domain.NewCreateInvestmentUpdateCommand(
+			investment, date, &amount, nil, nil, nil, amount, nil, nil,
+		))
+		if err != nil {
+			return fmt.Errorf("failed to create update: %w", err)
+		}
+		return nil
+	}
+
+	if lastUpdate.Date.Equal(date) {
+		var units, price *float64
+		if lastUpdate.Units != nil && lastUpdate.Price != nil && *lastUpdate.Price > 0 {
+			units = pointer.Of(*lastUpdate.Units + float64(amount) / *lastUpdate.Price)
+			price = lastUpdate.Price
+		}
+
+		err := repositories.InvestmentUpdate.Update(lastUpdate.ID, domain.NewCreateInvestmentUpdateCommand(
+			investment,
+			date,
+			pointer.Of(pointer.GetOrDefault(lastUpdate.Deposit, 0)+amount),
+			lastUpdate.Withdrawal,
+			lastUpdate.Income,
+			lastUpdate.Fee,
+			lastUpdate.Value+amount,
+			units,
+			price,
+		))
+		if err != nil {
+			return fmt.Errorf("failed to update update: %w", err)
+		}
+		return nil
+	}
+
+	_, err = repositories.InvestmentUpdate.Create(domain.NewCreateInvestmentUpdateCommand(
+		investment, date, &amount, nil, nil, nil, lastUpdate.Value+amount, nil, nil,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to create update: %w", err)
+	}
+	return nil
+}
+
+func (s ContributionPlanService) validate(plan domain.ContributionPlan) error {
+	if plan.Amount <= 0 {
+		return errors.Wrap(domain.ErrInvalidContributionPlan, "amount must be positive")
+	}
+	if !plan.Frequency.IsValid() {
+		return errors.Wrap(domain.ErrInvalidContributionPlan, "invalid frequency")
+	}
+	if plan.DayOfMonth < 1 || plan.DayOfMonth > 31 {
+		return errors.Wrap(domain.ErrInvalidContributionPlan, "day of month must be between 1 and 31")
+	}
+	return nil
+}
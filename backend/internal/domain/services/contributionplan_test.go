@@ -0,0 +1,125 @@
+package services
+
+import (
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeInvestmentUpdateRepository keeps the updates of a single investment in memory. It only implements what deposit
+// uses.
+type fakeInvestmentUpdateRepository struct {
+	InvestmentUpdateRepository
+	updates []domain.InvestmentUpdate
+	created []domain.CreateInvestmentUpdateCommand
+	updated map[string]domain.CreateInvestmentUpdateCommand
+}
+
+func (r *fakeInvestmentUpdateRepository) FindLastByInvestmentIDAndDateLessThanEqual(
+	investmentID string,
+	date time.Time,
+) (domain.InvestmentUpdate, error) {
+	var last *domain.InvestmentUpdate
+	for i, update := range r.updates {
+		if update.InvestmentID == investmentID && !update.Date.After(date) && (last == nil || update.Date.After(last.Date)) {
+			last = &r.updates[i]
+		}
+	}
+	if last == nil {
+		return domain.InvestmentUpdate{}, domain.ErrInvestmentUpdateNotFound
+	}
+	return *last, nil
+}
+
+func (r *fakeInvestmentUpdateRepository) Create(command domain.CreateInvestmentUpdateCommand) (domain.InvestmentUpdate, error) {
+	r.created = append(r.created, command)
+	return domain.InvestmentUpdate{}, nil
+}
+
+func (r *fakeInvestmentUpdateRepository) Update(id string, command domain.CreateInvestmentUpdateCommand) error {
+	r.updated[id] = command
+	return nil
+}
+
+func TestDeposit(t *testing.T) {
+	investment := domain.Investment{ID: "investment"}
+	date := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	dayBefore := date.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name    string
+		updates []domain.InvestmentUpdate
+		created []domain.CreateInvestmentUpdateCommand
+		updated map[string]domain.CreateInvestmentUpdateCommand
+	}{
+		{
+			name: "first update",
+			created: []domain.CreateInvestmentUpdateCommand{
+				domain.NewCreateInvestmentUpdateCommand(investment, date, pointer.Of(int64(500)), nil, nil, nil, 500, nil, nil),
+			},
+			updated: map[string]domain.CreateInvestmentUpdateCommand{},
+		},
+		{
+			name: "after an earlier update",
+			updates: []domain.InvestmentUpdate{
+				{ID: "earlier", InvestmentID: investment.ID, Date: dayBefore, Value: 1000, Units: pointer.Of(10.0), Price: pointer.Of(100.0)},
+			},
+			created: []domain.CreateInvestmentUpdateCommand{
+				domain.NewCreateInvestmentUpdateCommand(investment, date, pointer.Of(int64(500)), nil, nil, nil, 1500, nil, nil),
+			},
+			updated: map[string]domain.CreateInvestmentUpdateCommand{},
+		},
+		{
+			name: "on the date of an update with a price buys units at that price",
+			updates: []domain.InvestmentUpdate{
+				{
+					ID:           "same",
+					InvestmentID: investment.ID,
+					Date:         date,
+					Deposit:      pointer.Of(int64(200)),
+					Fee:          pointer.Of(int64(5)),
+					Value:        1000,
+					Units:        pointer.Of(10.0),
+					Price:        pointer.Of(100.0),
+				},
+			},
+			updated: map[string]domain.CreateInvestmentUpdateCommand{
+				"same": domain.NewCreateInvestmentUpdateCommand(
+					investment, date, pointer.Of(int64(700)), nil, nil, pointer.Of(int64(5)), 1500, pointer.Of(15.0), pointer.Of(100.0),
+				),
+			},
+		},
+		{
+			name: "on the date of an update with units but without a price leaves the units out",
+			updates: []domain.InvestmentUpdate{
+				{ID: "same", InvestmentID: investment.ID, Date: date, Value: 1000, Units: pointer.Of(10.0)},
+			},
+			updated: map[string]domain.CreateInvestmentUpdateCommand{
+				"same": domain.NewCreateInvestmentUpdateCommand(investment, date, pointer.Of(int64(500)), nil, nil, nil, 1500, nil, nil),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := &fakeInvestmentUpdateRepository{
+				updates: test.updates,
+				updated: make(map[string]domain.CreateInvestmentUpdateCommand),
+			}
+
+			err := deposit(Repositories{InvestmentUpdate: repository}, investment, date, 500)
+			if err != nil {
+				t.Fatalf("failed to deposit: %v", err)
+			}
+
+			if !reflect.DeepEqual(repository.created, test.created) {
+				t.Errorf("created = %+v, expected %+v", repository.created, test.created)
+			}
+			if !reflect.DeepEqual(repository.updated, test.updated) {
+				t.Errorf("updated = %+v, expected %+v", repository.updated, test.updated)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"math"
+	"time"
+)
+
+type FeeService struct {
+	investmentUpdateService InvestmentUpdateService
+	transactionService      TransactionService
+}
+
+func NewFeeService(investmentUpdateService InvestmentUpdateService, transactionService TransactionService) FeeService {
+	return FeeService{
+		investmentUpdateService: investmentUpdateService,
+		transactionService:      transactionService,
+	}
+}
+
+// FindFees sums the fees of every investment in its own currency, up to and including dateTo, from the updates and
+// from the fees and taxes in the ledger.
+func (s FeeService) FindFees(investments []domain.Investment, dateTo *time.Time) ([]domain.InvestmentFees, error) {
+	fees := make([]domain.InvestmentFees, 0)
+	for _, investment := range investments {
+		updates, err := s.investmentUpdateService.Find(domain.FindInvestmentUpdateQuery{
+			InvestmentIDs: []string{investment.ID},
+			DateTo:        dateTo,
+		})
+		if err != nil {
+			return []domain.InvestmentFees{}, fmt.Errorf("failed to find updates: %w", err)
+		}
+
+		transactions, err := s.transactionService.Find(domain.FindTransactionQuery{
+			InvestmentIDs: []string{investment.ID},
+			Types:         []domain.TransactionType{domain.TransactionTypeFee, domain.TransactionTypeTax},
+			DateTo:        dateTo,
+		})
+		if err != nil {
+			return []domain.InvestmentFees{}, fmt.Errorf("failed to find transactions: %w", err)
+		}
+
+		fees = append(fees, calculateFees(investment, updates, transactions))
+	}
+	return fees, nil
+}
+
+// calculateFees adds the fees charged since the previous update back to every update as if they had been paid out,
+// which gives the return before fees. The fees in the ledger since the previous update count for an update without a
+// fee of its own, as that fee would otherwise be counted twice. The updates must be sorted by date.
+func calculateFees(
+	investment domain.Investment,
+	updates []domain.InvestmentUpdate,
+	transactions []domain.Transaction,
+) domain.InvestmentFees {
+	feesByDate := make(map[time.Time]int64)
+
+	var oneOffFees, recurringFees, taxes int64
+	ledgerFees := make([]domain.Transaction, 0)
+	for _, transaction := range transactions {
+		if transaction.Type == domain.TransactionTypeTax {
+			taxes += transaction.Amount
+		} else {
+			ledgerFees = append(ledgerFees, transaction)
+		}
+	}
+
+	for i, update := range updates {
+		oneOffFee := pointer.GetOrDefault(update.Fee, 0)
+		if update.Fee == nil {
+			oneOffFee = sumLedgerFees(ledgerFees, updates, i)
+		}
+
+		fee := oneOffFee
+		if investment.AnnualFeePercentage != nil && i > 0 {
+			previous := updates[i-1]
+			years := update.Date.Sub(previous.Date).Hours() / 24 / 365
+			recurringFee := int64(math.Round(float64(previous.Value) * *investment.AnnualFeePercentage / 100 * years))
+			recurringFees += recurringFee
+			fee += recurringFee
+		}
+		oneOffFees += oneOffFee
+		feesByDate[update.Date] += fee
+	}
+	// fees after the last update do not change a return yet, but have been paid
+	oneOffFees += sumLedgerFees(ledgerFees, updates, len(updates))
+
+	pointsAfterFees := toValuePoints(updates)
+	pointsBeforeFees := make([]valuePoint, len(pointsAfterFees))
+	for i, point := range pointsAfterFees {
+		point.Flow -= feesByDate[point.Date]
+		pointsBeforeFees[i] = point
+	}
+
+	return domain.NewInvestmentFees(
+		investment.ID,
+		oneOffFees,
+		recurringFees,
+		taxes,
+		chainTimeWeightedReturn(pointsBeforeFees, nil, nil),
+		chainTimeWeightedReturn(pointsAfterFees, nil, nil),
+	)
+}
+
+// sumLedgerFees sums the fees in the ledger after the update before index i and up to and including the update at
+// index i. An index past the last update takes all fees after the last update.
+func sumLedgerFees(fees []domain.Transaction, updates []domain.InvestmentUpdate, i int) int64 {
+	var sum int64
+	for _, fee := range fees {
+		if i > 0 && !fee.Date.After(updates[i-1].Date) {
+			continue
+		}
+		if i < len(updates) && fee.Date.After(updates[i].Date) {
+			continue
+		}
+		sum += fee.Amount
+	}
+	return sum
+}
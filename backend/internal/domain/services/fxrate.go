@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"time"
+)
+
+type FXRateRepository interface {
+	FindByCurrencies(currencies []domain.Currency) ([]domain.FXRate, error)
+
+	Save(rates []domain.FXRate) error
+}
+
+type FXRateService struct {
+	fxRateRepository FXRateRepository
+}
+
+func NewFXRateService(fxRateRepository FXRateRepository) FXRateService {
+	return FXRateService{
+		fxRateRepository: fxRateRepository,
+	}
+}
+
+func (s FXRateService) Save(rates []domain.FXRate) error {
+	return s.fxRateRepository.Save(rates)
+}
+
+// FindRates loads the rates needed to convert the investments to and from the reporting currency.
+func (s FXRateService) FindRates(investments []domain.Investment, currency domain.Currency) (domain.FXRates, error) {
+	currencies := slices.Deduplicate(append(
+		slices.Map(investments, func(i domain.Investment) domain.Currency { return i.Currency }),
+		currency,
+	))
+	if len(currencies) == 1 {
+		return domain.NewFXRates([]domain.FXRate{}), nil
+	}
+
+	rates, err := s.fxRateRepository.FindByCurrencies(currencies)
+	if err != nil {
+		return domain.FXRates{}, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	return domain.NewFXRates(rates), nil
+}
+
+// convertUpdate converts all amounts of the update at the rate of the given date.
+func convertUpdate(
+	rates domain.FXRates,
+	update domain.InvestmentUpdate,
+	from,
+	to domain.Currency,
+	date time.Time,
+) (domain.InvestmentUpdate, error) {
+	if from == to {
+		return update, nil
+	}
+
+	convert := func(amount *int64) (*int64, error) {
+		if amount == nil {
+			return nil, nil
+		}
+		converted, err := rates.Convert(*amount, from, to, date)
+		return &converted, err
+	}
+
+	deposit, err := convert(update.Deposit)
+	if err != nil {
+		return domain.InvestmentUpdate{}, err
+	}
+	withdrawal, err := convert(update.Withdrawal)
+	if err != nil {
+		return domain.InvestmentUpdate{}, err
+	}
+	income, err := convert(update.Income)
+	if err != nil {
+		return domain.InvestmentUpdate{}, err
+	}
+	fee, err := convert(update.Fee)
+	if err != nil {
+		return domain.InvestmentUpdate{}, err
+	}
+	cost, err := convert(&update.Cost)
+	if err != nil {
+		return domain.InvestmentUpdate{}, err
+	}
+	fees, err := convert(&update.Fees)
+	if err != nil {
+		return domain.InvestmentUpdate{}, err
+	}
+	value, err := convert(&update.Value)
+	if err != nil {
+		return domain.InvestmentUpdate{}, err
+	}
+
+	converted := update
+	converted.Deposit = deposit
+	converted.Withdrawal = withdrawal
+	converted.Income = income
+	converted.Fee = fee
+	converted.Cost = *cost
+	converted.Fees = *fees
+	converted.Value = *value
+	return converted, nil
+}
+
+func currencyByInvestmentID(investments []domain.Investment) map[string]domain.Currency {
+	currencies := make(map[string]domain.Currency)
+	for _, investment := range investments {
+		currencies[investment.ID] = investment.Currency
+	}
+	return currencies
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"math"
+	"sort"
+)
+
+type GainService struct {
+	investmentUpdateService InvestmentUpdateService
+}
+
+func NewGainService(investmentUpdateService InvestmentUpdateService) GainService {
+	return GainService{
+		investmentUpdateService: investmentUpdateService,
+	}
+}
+
+// FindGains attributes the gains of every investment in its own currency.
+func (s GainService) FindGains(
+	investments []domain.Investment,
+	method domain.GainAttributionMethod,
+) ([]domain.Gains, error) {
+	gains := make([]domain.Gains, 0)
+	for _, investment := range investments {
+		updates, err := s.investmentUpdateService.FindByInvestmentID(investment.ID)
+		if err != nil {
+			return []domain.Gains{}, fmt.Errorf("failed to find updates: %w", err)
+		}
+
+		gains = append(gains, attributeGains(investment.ID, updates, method))
+	}
+	return gains, nil
+}
+
+// attributeGains walks through the updates and keeps track of the principal that is still invested. Deposits add to
+// the principal and every withdrawal is split into returned principal and realized gain. The value of an update is
+// the value after its withdrawal, so the value before the withdrawal is the sum of both. Income is realized gain as a
+// whole.
+func attributeGains(investmentID string, updates []domain.InvestmentUpdate, method domain.GainAttributionMethod) domain.Gains {
+	sorted := make([]domain.InvestmentUpdate, len(updates))
+	copy(sorted, updates)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Date.Before(sorted[b].Date) })
+
+	withdrawals := make([]domain.AttributedWithdrawal, 0)
+	byYear := make([]domain.YearlyGains, 0)
+
+	var principal, realized, unrealized int64
+	for _, update := range sorted {
+		principal += pointer.GetOrDefault(update.Deposit, 0)
+
+		var realizedInUpdate int64
+		if update.Withdrawal != nil {
+			withdrawal := *update.Withdrawal
+			returnedPrincipal := attributePrincipal(withdrawal, principal, update.Value+withdrawal, method)
+			principal -= returnedPrincipal
+			realizedInUpdate += withdrawal - returnedPrincipal
+			withdrawals = append(withdrawals, domain.NewAttributedWithdrawal(
+				update.Date,
+				withdrawal,
+				returnedPrincipal,
+				withdrawal-returnedPrincipal,
+			))
+		}
+		realizedInUpdate += pointer.GetOrDefault(update.Income, 0)
+
+		realized += realizedInUpdate
+		unrealized = update.Value - principal
+
+		year := update.Date.Year()
+		if len(byYear) == 0 || byYear[len(byYear)-1].Year != year {
+			byYear = append(byYear, domain.NewYearlyGains(year, 0, 0))
+		}
+		byYear[len(byYear)-1].Realized += realizedInUpdate
+		byYear[len(byYear)-1].Unrealized = unrealized
+	}
+
+	return domain.NewGains(investmentID, realized, unrealized, withdrawals, byYear)
+}
+
+// attributePrincipal returns the part of the withdrawal that returns principal. It never returns more principal than
+// is still invested. Without a value to divide, the proportional method falls back to cost-first.
+func attributePrincipal(withdrawal, principal, valueBefore int64, method domain.GainAttributionMethod) int64 {
+	if principal <= 0 {
+		return 0
+	}
+	if method == domain.GainAttributionMethodProportional && valueBefore > 0 {
+		return min(principal, int64(math.Round(float64(principal)*float64(withdrawal)/float64(valueBefore))))
+	}
+	return min(principal, withdrawal)
+}
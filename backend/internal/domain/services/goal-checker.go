@@ -0,0 +1,35 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+type GoalChecker struct {
+	goalService GoalService
+}
+
+func NewGoalChecker(
+	goalService GoalService,
+) GoalChecker {
+	return GoalChecker{
+		goalService: goalService,
+	}
+}
+
+func (c GoalChecker) Check() {
+	slog.Info("Checking goals...")
+
+	goals, err := c.goalService.FindAll()
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to find goals: %+v", err))
+		return
+	}
+
+	for _, goal := range goals {
+		_, err := c.goalService.Check(goal)
+		if err != nil {
+			slog.Error(fmt.Sprintf("failed to check goal %s: %+v", goal.ID, err))
+		}
+	}
+}
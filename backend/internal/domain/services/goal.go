@@ -0,0 +1,323 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"growfolio/internal/slices"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxProjectionMonths is how far ahead the completion of a goal is projected before it is considered unreachable.
+const maxProjectionMonths = 100 * 12
+
+type GoalRepository interface {
+	FindByID(id string) (domain.Goal, error)
+	FindByUserID(userID string) ([]domain.Goal, error)
+	FindAll() ([]domain.Goal, error)
+
+	Create(userID string, command domain.SaveGoalCommand) (domain.Goal, error)
+	Update(id string, command domain.SaveGoalCommand) (domain.Goal, error)
+	UpdateStatus(id string, status domain.GoalStatus) error
+	DeleteByID(id string) error
+	DeleteByUserID(userID string) error
+}
+
+type GoalService struct {
+	goalRepository          GoalRepository
+	investmentService       InvestmentService
+	investmentUpdateService InvestmentUpdateService
+	fxRateService           FXRateService
+	settingsService         SettingsService
+	tagService              TagService
+	eventPublisher          EventPublisher
+}
+
+func NewGoalService(
+	goalRepository GoalRepository,
+	investmentService InvestmentService,
+	investmentUpdateService InvestmentUpdateService,
+	fxRateService FXRateService,
+	settingsService SettingsService,
+	tagService TagService,
+	eventPublisher EventPublisher,
+) GoalService {
+	return GoalService{
+		goalRepository:          goalRepository,
+		investmentService:       investmentService,
+		investmentUpdateService: investmentUpdateService,
+		fxRateService:           fxRateService,
+		settingsService:         settingsService,
+		tagService:              tagService,
+		eventPublisher:          eventPublisher,
+	}
+}
+
+func (s GoalService) FindByID(id string) (domain.Goal, error) {
+	return s.goalRepository.FindByID(id)
+}
+
+func (s GoalService) FindByUserID(userID string) ([]domain.Goal, error) {
+	return s.goalRepository.FindByUserID(userID)
+}
+
+func (s GoalService) FindAll() ([]domain.Goal, error) {
+	return s.goalRepository.FindAll()
+}
+
+// Create creates the goal and records its first status.
+func (s GoalService) Create(userID string, command domain.SaveGoalCommand) (domain.Goal, error) {
+	command, err := s.validate(userID, command)
+	if err != nil {
+		return domain.Goal{}, err
+	}
+
+	goal, err := s.goalRepository.Create(userID, command)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	_, err = s.Check(goal)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to check goal: %w", err)
+	}
+
+	return s.goalRepository.FindByID(goal.ID)
+}
+
+func (s GoalService) Update(goal domain.Goal, command domain.SaveGoalCommand) (domain.Goal, error) {
+	command, err := s.validate(goal.UserID, command)
+	if err != nil {
+		return domain.Goal{}, err
+	}
+
+	return s.goalRepository.Update(goal.ID, command)
+}
+
+func (s GoalService) DeleteByID(id string) error {
+	return s.goalRepository.DeleteByID(id)
+}
+
+func (s GoalService) DeleteByUserID(userID string) error {
+	return s.goalRepository.DeleteByUserID(userID)
+}
+
+// FindProgress values the investments of the goal at their last update, converted at the rate of today.
+func (s GoalService) FindProgress(goal domain.Goal) (domain.GoalProgress, error) {
+	investments, err := s.investmentService.FindByUserID(goal.UserID)
+	if err != nil {
+		return domain.GoalProgress{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+	investments = slices.Filter(investments, goal.CountsToward)
+
+	rates, err := s.fxRateService.FindRates(investments, goal.Currency)
+	if err != nil {
+		return domain.GoalProgress{}, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	date := today()
+
+	var currentValue int64
+	for _, investment := range investments {
+		if investment.LastUpdate == nil {
+			continue
+		}
+		converted, err := convertUpdate(rates, *investment.LastUpdate, investment.Currency, goal.Currency, date)
+		if err != nil {
+			return domain.GoalProgress{}, fmt.Errorf("failed to convert last update: %w", err)
+		}
+		currentValue += converted.Value
+	}
+
+	monthlyContribution, err := s.findMonthlyContribution(investments, rates, goal.Currency, date)
+	if err != nil {
+		return domain.GoalProgress{}, err
+	}
+
+	return calculateGoalProgress(goal, currentValue, monthlyContribution, date), nil
+}
+
+// Check records the current status of the goal. When the goal was reached or fell behind schedule since the previous
+// check, an event is published. The first check of a goal only records its status.
+func (s GoalService) Check(goal domain.Goal) (domain.GoalProgress, error) {
+	progress, err := s.FindProgress(goal)
+	if err != nil {
+		return domain.GoalProgress{}, err
+	}
+	if goal.Status != nil && *goal.Status == progress.Status {
+		return progress, nil
+	}
+
+	err = s.goalRepository.UpdateStatus(goal.ID, progress.Status)
+	if err != nil {
+		return domain.GoalProgress{}, fmt.Errorf("failed to update status: %w", err)
+	}
+
+	if goal.Status != nil {
+		switch progress.Status {
+		case domain.GoalStatusReached:
+			s.eventPublisher.Publish(domain.NewGoalReachedEvent(progress))
+		case domain.GoalStatusBehindSchedule:
+			s.eventPublisher.Publish(domain.NewGoalBehindScheduleEvent(progress))
+		}
+	}
+
+	return progress, nil
+}
+
+// findMonthlyContribution averages the deposits minus the withdrawals of the investments over the last 12 months.
+func (s GoalService) findMonthlyContribution(
+	investments []domain.Investment,
+	rates domain.FXRates,
+	currency domain.Currency,
+	date time.Time,
+) (int64, error) {
+	updates, err := s.investmentUpdateService.Find(domain.FindInvestmentUpdateQuery{
+		InvestmentIDs: slices.Map(investments, func(i domain.Investment) string { return i.ID }),
+		DateFrom:      pointer.Of(date.AddDate(-1, 0, 1)),
+		DateTo:        &date,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find updates: %w", err)
+	}
+
+	currencies := currencyByInvestmentID(investments)
+
+	var contributions int64
+	for _, update := range updates {
+		converted, err := convertUpdate(rates, update, currencies[update.InvestmentID], currency, update.Date)
+		if err != nil {
+			return 0, fmt.Errorf("failed to convert update: %w", err)
+		}
+		contributions += pointer.GetOrDefault(converted.Deposit, 0) - pointer.GetOrDefault(converted.Withdrawal, 0)
+	}
+
+	return contributions / 12, nil
+}
+
+func (s GoalService) validate(userID string, command domain.SaveGoalCommand) (domain.SaveGoalCommand, error) {
+	command.Name = strings.TrimSpace(command.Name)
+	if command.Name == "" {
+		return domain.SaveGoalCommand{}, errors.Wrap(domain.ErrInvalidGoal, "name cannot be empty")
+	}
+	if command.TargetAmount <= 0 {
+		return domain.SaveGoalCommand{}, errors.Wrap(domain.ErrInvalidGoal, "target amount must be positive")
+	}
+	if command.ExpectedReturnPercentage <= -100 {
+		return domain.SaveGoalCommand{}, errors.Wrap(domain.ErrInvalidGoal, "expected return must be above -100%")
+	}
+	if len(command.InvestmentIDs) == 0 && len(command.TagIDs) == 0 {
+		return domain.SaveGoalCommand{}, errors.Wrap(domain.ErrInvalidGoal, "a goal needs at least one investment or tag")
+	}
+
+	investments, err := s.investmentService.FindByUserID(userID)
+	if err != nil {
+		return domain.SaveGoalCommand{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+	investmentsByID := slices.AssociateBy(investments, func(i domain.Investment) string { return i.ID })
+	for _, investmentID := range command.InvestmentIDs {
+		if _, ok := investmentsByID[investmentID]; !ok {
+			return domain.SaveGoalCommand{}, errors.Wrapf(domain.ErrInvalidGoal, "unknown investment %s", investmentID)
+		}
+	}
+
+	tags, err := s.tagService.FindByUserID(userID)
+	if err != nil {
+		return domain.SaveGoalCommand{}, fmt.Errorf("failed to find tags: %w", err)
+	}
+	tagsByID := slices.AssociateBy(tags, func(t domain.Tag) string { return t.ID })
+	for _, tagID := range command.TagIDs {
+		if _, ok := tagsByID[tagID]; !ok {
+			return domain.SaveGoalCommand{}, errors.Wrapf(domain.ErrInvalidGoal, "unknown tag %s", tagID)
+		}
+	}
+
+	command.InvestmentIDs = slices.Deduplicate(command.InvestmentIDs)
+	command.TagIDs = slices.Deduplicate(command.TagIDs)
+
+	if command.Currency == "" {
+		settings, err := s.settingsService.FindByUserID(userID)
+		if err != nil {
+			return domain.SaveGoalCommand{}, fmt.Errorf("failed to find settings: %w", err)
+		}
+		command.Currency = settings.Currency
+	}
+
+	return command, nil
+}
+
+// calculateGoalProgress compounds the expected return monthly. A goal is behind schedule when the projected
+// completion date is after the target date.
+func calculateGoalProgress(
+	goal domain.Goal,
+	currentValue,
+	monthlyContribution int64,
+	date time.Time,
+) domain.GoalProgress {
+	monthlyReturn := math.Pow(1+goal.ExpectedReturnPercentage/100, 1.0/12) - 1
+	months := monthsBetween(date, goal.TargetDate)
+
+	percentage := float64(currentValue) / float64(goal.TargetAmount) * 100
+	required := requiredMonthlyContribution(currentValue, goal.TargetAmount, monthlyReturn, months)
+	projected := projectCompletionDate(currentValue, goal.TargetAmount, monthlyContribution, monthlyReturn, date)
+
+	status := domain.GoalStatusOnTrack
+	if currentValue >= goal.TargetAmount {
+		status = domain.GoalStatusReached
+	} else if projected == nil || projected.After(goal.TargetDate) {
+		status = domain.GoalStatusBehindSchedule
+	}
+
+	return domain.NewGoalProgress(goal, currentValue, percentage, monthlyContribution, required, projected, status)
+}
+
+// requiredMonthlyContribution solves the future value of an annuity for the payment. The current value grows for the
+// given number of months and a contribution at the end of every month makes up the rest of the target. When there is
+// no month left, the whole shortfall is required right away.
+func requiredMonthlyContribution(currentValue, target int64, monthlyReturn float64, months int) int64 {
+	if months <= 0 {
+		return max(0, target-currentValue)
+	}
+
+	growth := math.Pow(1+monthlyReturn, float64(months))
+	shortfall := float64(target) - float64(currentValue)*growth
+	if shortfall <= 0 {
+		return 0
+	}
+	if monthlyReturn == 0 {
+		return int64(math.Ceil(shortfall / float64(months)))
+	}
+	return int64(math.Ceil(shortfall * monthlyReturn / (growth - 1)))
+}
+
+// projectCompletionDate grows the value month by month with the expected return and the monthly contribution, and
+// returns the first month in which the target is reached.
+func projectCompletionDate(
+	currentValue,
+	target,
+	monthlyContribution int64,
+	monthlyReturn float64,
+	date time.Time,
+) *time.Time {
+	value := float64(currentValue)
+	for month := 0; month <= maxProjectionMonths; month++ {
+		if value >= float64(target) {
+			return pointer.Of(date.AddDate(0, month, 0))
+		}
+		value = value*(1+monthlyReturn) + float64(monthlyContribution)
+	}
+	return nil
+}
+
+// monthsBetween counts the whole months from one date to another.
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return months
+}
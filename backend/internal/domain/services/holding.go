@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"math"
+	"sort"
+)
+
+type HoldingService struct {
+	investmentUpdateService InvestmentUpdateService
+}
+
+func NewHoldingService(investmentUpdateService InvestmentUpdateService) HoldingService {
+	return HoldingService{
+		investmentUpdateService: investmentUpdateService,
+	}
+}
+
+// FindByInvestmentID returns the unit position after every update, starting at the first update that has units.
+func (s HoldingService) FindByInvestmentID(investmentID string) ([]domain.Holding, error) {
+	updates, err := s.investmentUpdateService.FindByInvestmentID(investmentID)
+	if err != nil {
+		return []domain.Holding{}, fmt.Errorf("failed to find updates: %w", err)
+	}
+
+	return calculateHoldings(updates), nil
+}
+
+// calculateHoldings tracks the units across updates with the average cost method. Buying units adds the deposit to
+// the cost basis and selling units removes their share of the cost basis. Tracking starts with the cost of the first
+// update that has units, and updates without units carry the units of the previous update forward.
+func calculateHoldings(updates []domain.InvestmentUpdate) []domain.Holding {
+	sorted := make([]domain.InvestmentUpdate, len(updates))
+	copy(sorted, updates)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Date.Before(sorted[b].Date) })
+
+	holdings := make([]domain.Holding, 0)
+
+	var units, costBasis float64
+	var tracking bool
+	for _, update := range sorted {
+		if update.Units == nil && !tracking {
+			continue
+		}
+
+		newUnits := units
+		if update.Units != nil {
+			newUnits = *update.Units
+		}
+
+		price := 0.0
+		if update.Price != nil {
+			price = *update.Price
+		} else if newUnits != 0 {
+			price = float64(update.Value) / newUnits
+		}
+
+		deposit := float64(pointer.GetOrDefault(update.Deposit, 0))
+		switch {
+		case !tracking:
+			costBasis = float64(update.Cost)
+			tracking = true
+		case newUnits > units:
+			if update.Deposit != nil {
+				costBasis += deposit
+			} else {
+				costBasis += (newUnits - units) * price
+			}
+		case newUnits < units:
+			costBasis -= costBasis * (units - newUnits) / units
+		default:
+			costBasis += deposit
+		}
+		units = newUnits
+
+		var averageCost float64
+		if units != 0 {
+			averageCost = costBasis / units
+		}
+
+		holdings = append(holdings, domain.NewHolding(
+			update.Date,
+			units,
+			price,
+			int64(math.Round(costBasis)),
+			averageCost,
+			update.Value-int64(math.Round(costBasis)),
+			price-averageCost,
+		))
+	}
+
+	return holdings
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+type ImportProfileRepository interface {
+	FindByID(id string) (domain.ImportProfile, error)
+	FindByUserID(userID string) ([]domain.ImportProfile, error)
+
+	Create(userID string, command domain.SaveImportProfileCommand) (domain.ImportProfile, error)
+	Update(id string, command domain.SaveImportProfileCommand) (domain.ImportProfile, error)
+	DeleteByID(id string) error
+	DeleteByUserID(userID string) error
+}
+
+type ImportProfileService struct {
+	importProfileRepository ImportProfileRepository
+}
+
+func NewImportProfileService(importProfileRepository ImportProfileRepository) ImportProfileService {
+	return ImportProfileService{
+		importProfileRepository: importProfileRepository,
+	}
+}
+
+func (s ImportProfileService) FindByID(id string) (domain.ImportProfile, error) {
+	return s.importProfileRepository.FindByID(id)
+}
+
+func (s ImportProfileService) FindByUserID(userID string) ([]domain.ImportProfile, error) {
+	return s.importProfileRepository.FindByUserID(userID)
+}
+
+func (s ImportProfileService) Create(userID string, command domain.SaveImportProfileCommand) (domain.ImportProfile, error) {
+	command, err := s.validate(userID, "", command)
+	if err != nil {
+		return domain.ImportProfile{}, err
+	}
+
+	return s.importProfileRepository.Create(userID, command)
+}
+
+func (s ImportProfileService) Update(
+	profile domain.ImportProfile,
+	command domain.SaveImportProfileCommand,
+) (domain.ImportProfile, error) {
+	command, err := s.validate(profile.UserID, profile.ID, command)
+	if err != nil {
+		return domain.ImportProfile{}, err
+	}
+
+	return s.importProfileRepository.Update(profile.ID, command)
+}
+
+func (s ImportProfileService) DeleteByID(id string) error {
+	return s.importProfileRepository.DeleteByID(id)
+}
+
+func (s ImportProfileService) DeleteByUserID(userID string) error {
+	return s.importProfileRepository.DeleteByUserID(userID)
+}
+
+// validate checks that every date and number the profile describes can be read back. A column mapping has to map the
+// date and either the value or both the units and the price, because the value can follow from those.
+func (s ImportProfileService) validate(
+	userID,
+	profileID string,
+	command domain.SaveImportProfileCommand,
+) (domain.SaveImportProfileCommand, error) {
+	command.Name = strings.TrimSpace(command.Name)
+	if command.Name == "" {
+		return command, errors.Wrap(domain.ErrInvalidImportProfile, "name cannot be empty")
+	}
+
+	if utf8.RuneCountInString(command.Delimiter) != 1 || strings.ContainsAny(command.Delimiter, "\"\r\n") {
+		return command, errors.Wrapf(domain.ErrInvalidImportProfile, "invalid delimiter %q", command.Delimiter)
+	}
+	if utf8.RuneCountInString(command.DecimalSeparator) != 1 {
+		return command, errors.Wrapf(domain.ErrInvalidImportProfile, "invalid decimal separator %q", command.DecimalSeparator)
+	}
+	if utf8.RuneCountInString(command.ThousandsSeparator) > 1 || command.ThousandsSeparator == command.DecimalSeparator {
+		return command, errors.Wrapf(domain.ErrInvalidImportProfile, "invalid thousands separator %q", command.ThousandsSeparator)
+	}
+	if !command.AmountScale.IsValid() {
+		return command, errors.Wrapf(domain.ErrInvalidImportProfile, "invalid amount scale %q", command.AmountScale)
+	}
+
+	profile := domain.NewImportProfile("", userID, command.Name, command.Delimiter, command.DateFormat, "", "", nil, "")
+	date := time.Date(2023, 11, 25, 0, 0, 0, 0, time.UTC)
+	parsed, err := profile.ParseDate(date.Format(profile.DateLayout()))
+	if err != nil || !parsed.Equal(date) {
+		return command, errors.Wrapf(domain.ErrInvalidImportProfile, "invalid date format %q", command.DateFormat)
+	}
+
+	for field, header := range command.ColumnMapping {
+		if !slices.Contains(domain.ImportFields, field) {
+			return command, errors.Wrapf(domain.ErrInvalidImportProfile, "unknown field %q", field)
+		}
+		if strings.TrimSpace(header) == "" {
+			return command, errors.Wrapf(domain.ErrInvalidImportProfile, "header of field %q cannot be empty", field)
+		}
+		command.ColumnMapping[field] = strings.TrimSpace(header)
+	}
+	if len(command.ColumnMapping) > 0 {
+		_, hasDate := command.ColumnMapping[domain.ImportFieldDate]
+		_, hasValue := command.ColumnMapping[domain.ImportFieldValue]
+		_, hasUnits := command.ColumnMapping[domain.ImportFieldUnits]
+		_, hasPrice := command.ColumnMapping[domain.ImportFieldPrice]
+		if !hasDate {
+			return command, errors.Wrap(domain.ErrInvalidImportProfile, "column mapping has no date")
+		}
+		if !hasValue && (!hasUnits || !hasPrice) {
+			return command, errors.Wrap(domain.ErrInvalidImportProfile, "column mapping has no value")
+		}
+	}
+
+	profiles, err := s.importProfileRepository.FindByUserID(userID)
+	if err != nil {
+		return command, fmt.Errorf("failed to find import profiles: %w", err)
+	}
+	for _, p := range profiles {
+		if p.ID != profileID && strings.EqualFold(p.Name, command.Name) {
+			return command, errors.Wrapf(domain.ErrInvalidImportProfile, "import profile %q already exists", command.Name)
+		}
+	}
+
+	return command, nil
+}
@@ -3,32 +3,45 @@ package services
 import (
 	"fmt"
 	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
 type InvestmentRepository interface {
+	FindAll() ([]domain.Investment, error)
 	FindByUserID(userID string) ([]domain.Investment, error)
+	FindByPortfolioID(portfolioID string) ([]domain.Investment, error)
 	FindByID(id string) (domain.Investment, error)
 
 	Create(command domain.CreateInvestmentCommand) (domain.Investment, error)
 	DeleteByID(id string) error
 	UpdateLocked(id string, locked bool) error
+	Update(id string, t domain.InvestmentType, name string, staleAfterDays *int) (domain.Investment, error)
+	UpdateDisplayOrders(displayOrderByID map[string]int) error
+	UpdateClosedAt(id string, closedAt *time.Time) error
+	UpdateStaleRemindedAt(id string, staleRemindedAt time.Time) error
 }
 
 type InvestmentService struct {
 	investmentRepository    InvestmentRepository
 	investmentUpdateService InvestmentUpdateService
+	eventPublisher          EventPublisher
+	unitOfWork              UnitOfWork
 }
 
 func NewInvestmentService(
 	investmentRepository InvestmentRepository,
 	investmentUpdateService InvestmentUpdateService,
+	eventPublisher EventPublisher,
+	unitOfWork UnitOfWork,
 ) InvestmentService {
 	return InvestmentService{
 		investmentRepository:    investmentRepository,
 		investmentUpdateService: investmentUpdateService,
+		eventPublisher:          eventPublisher,
+		unitOfWork:              unitOfWork,
 	}
 }
 
@@ -36,17 +49,59 @@ func (s InvestmentService) UpdateLocked(id string, locked bool) error {
 	return s.investmentRepository.UpdateLocked(id, locked)
 }
 
+func (s InvestmentService) FindAllActive() ([]domain.Investment, error) {
+	investments, err := s.investmentRepository.FindAll()
+	if err != nil {
+		return []domain.Investment{}, err
+	}
+	return active(investments), nil
+}
+
+// FindByUserID finds all investments of the user, including the closed ones, which still count toward the history
+// of the portfolio.
 func (s InvestmentService) FindByUserID(userID string) ([]domain.Investment, error) {
 	return s.investmentRepository.FindByUserID(userID)
 }
 
+func (s InvestmentService) FindActiveByUserID(userID string) ([]domain.Investment, error) {
+	investments, err := s.investmentRepository.FindByUserID(userID)
+	if err != nil {
+		return []domain.Investment{}, err
+	}
+	return active(investments), nil
+}
+
+// FindByPortfolioID finds all investments of the portfolio, including the closed ones.
+func (s InvestmentService) FindByPortfolioID(portfolioID string) ([]domain.Investment, error) {
+	return s.investmentRepository.FindByPortfolioID(portfolioID)
+}
+
+func (s InvestmentService) FindActiveByPortfolioID(portfolioID string) ([]domain.Investment, error) {
+	investments, err := s.investmentRepository.FindByPortfolioID(portfolioID)
+	if err != nil {
+		return []domain.Investment{}, err
+	}
+	return active(investments), nil
+}
+
+func active(investments []domain.Investment) []domain.Investment {
+	active := make([]domain.Investment, 0)
+	for _, investment := range investments {
+		if !investment.IsClosed() {
+			active = append(active, investment)
+		}
+	}
+	return active
+}
+
 func (s InvestmentService) FindByID(id string) (domain.Investment, error) {
 	return s.investmentRepository.FindByID(id)
 }
 
-// TODO: Move part of this to the infra layer and use a DB transaction
+// Create creates the investment together with its optional initial update in a single unit of work, so the investment
+// is not left behind without it when the update fails.
 func (s InvestmentService) Create(command domain.CreateInvestmentCommand) (domain.Investment, error) {
-	investments, err := s.investmentRepository.FindByUserID(command.User.ID)
+	investments, err := s.FindActiveByUserID(command.User.ID)
 	if err != nil {
 		return domain.Investment{}, fmt.Errorf("failed to find investments: %w", err)
 	}
@@ -54,39 +109,273 @@ func (s InvestmentService) Create(command domain.CreateInvestmentCommand) (domai
 		return domain.Investment{}, domain.ErrMaxInvestmentsReached
 	}
 
-	investment, err := s.investmentRepository.Create(command)
+	if command.Portfolio.UserID != command.User.ID {
+		return domain.Investment{}, errors.Wrap(domain.ErrInvalidInvestment, "portfolio belongs to another user")
+	}
+	if command.Currency == "" {
+		command.Currency = command.Portfolio.Currency
+	}
+	if command.Locked && command.InitialUpdate != nil {
+		return domain.Investment{}, domain.ErrInvestmentIsLocked
+	}
+
+	var investment domain.Investment
+	err = s.unitOfWork.Do(func(repositories Repositories) error {
+		investment, err = repositories.Investment.Create(command)
+		if err != nil {
+			return fmt.Errorf("failed to create investment: %w", err)
+		}
+
+		if command.InitialUpdate != nil {
+			initialUpdate := command.InitialUpdate
+
+			date := time.Now()
+			if initialUpdate.Date != nil {
+				date = *initialUpdate.Date
+			}
+
+			_, err = repositories.InvestmentUpdate.Create(domain.NewCreateInvestmentUpdateCommand(
+				investment,
+				date,
+				initialUpdate.Deposit,
+				nil,
+				nil,
+				nil,
+				initialUpdate.Value,
+				nil,
+				nil,
+			))
+			if err != nil {
+				return fmt.Errorf("failed to create update: %w", err)
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return domain.Investment{}, fmt.Errorf("failed to create investment: %w", err)
+		return domain.Investment{}, err
+	}
+
+	return investment, nil
+}
+
+func (s InvestmentService) Update(
+	investment domain.Investment,
+	command domain.UpdateInvestmentCommand,
+) (domain.Investment, error) {
+	if investment.Locked {
+		return domain.Investment{}, domain.ErrInvestmentIsLocked
 	}
 
-	if command.InitialUpdate != nil {
-		initialUpdate := command.InitialUpdate
+	t := investment.Type
+	if command.Type != nil {
+		if !command.Type.IsValid() {
+			return domain.Investment{}, errors.Wrapf(domain.ErrInvalidInvestment, "unknown type %q", *command.Type)
+		}
+		t = *command.Type
+	}
+	name := investment.Name
+	if command.Name != nil {
+		if *command.Name == "" {
+			return domain.Investment{}, errors.Wrap(domain.ErrInvalidInvestment, "name cannot be empty")
+		}
+		name = *command.Name
+	}
+	staleAfterDays := investment.StaleAfterDays
+	if command.StaleAfterDays != nil {
+		if *command.StaleAfterDays < 0 {
+			return domain.Investment{}, errors.Wrap(domain.ErrInvalidInvestment, "stale after days cannot be negative")
+		}
+		staleAfterDays = command.StaleAfterDays
+		if *command.StaleAfterDays == 0 {
+			staleAfterDays = nil
+		}
+	}
 
-		date := time.Now()
-		if initialUpdate.Date != nil {
-			date = *initialUpdate.Date
+	// the investment is moved and updated in a single unit of work, so a failed update does not leave it moved
+	var updated domain.Investment
+	err := s.unitOfWork.Do(func(repositories Repositories) error {
+		if command.DisplayOrder != nil {
+			err := move(repositories, investment, *command.DisplayOrder)
+			if err != nil {
+				return fmt.Errorf("failed to move investment: %w", err)
+			}
 		}
 
-		_, err = s.investmentUpdateService.Create(domain.NewCreateInvestmentUpdateCommand(
-			investment,
-			date,
-			initialUpdate.Deposit,
-			nil,
-			initialUpdate.Value,
-		))
+		var err error
+		updated, err = repositories.Investment.Update(investment.ID, t, name, staleAfterDays)
 		if err != nil {
-			return domain.Investment{}, fmt.Errorf("failed to create update: %w", err)
+			return fmt.Errorf("failed to update investment: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return domain.Investment{}, err
 	}
 
-	return investment, nil
+	if updated.Name != investment.Name {
+		s.eventPublisher.Publish(domain.NewInvestmentRenamedEvent(updated, investment.Name))
+	}
+
+	return updated, nil
 }
 
+// RemindIfStale publishes a reminder when the investment is stale on the given date. The user is reminded once per
+// stale period, so a new reminder only follows after the investment has been updated and has gone stale again.
+func (s InvestmentService) RemindIfStale(
+	investment domain.Investment,
+	defaultStaleAfterDays int,
+	date time.Time,
+) (bool, error) {
+	if investment.Locked || !investment.IsStale(date, defaultStaleAfterDays) {
+		return false, nil
+	}
+
+	staleSince := *investment.StaleSince(defaultStaleAfterDays)
+	if investment.StaleRemindedAt != nil && !investment.StaleRemindedAt.Before(staleSince) {
+		return false, nil
+	}
+
+	err := s.investmentRepository.UpdateStaleRemindedAt(investment.ID, date)
+	if err != nil {
+		return false, fmt.Errorf("failed to update stale reminded at: %w", err)
+	}
+
+	s.eventPublisher.Publish(domain.NewInvestmentStaleEvent(investment, staleSince))
+
+	return true, nil
+}
+
+// Close marks the investment as closed on the given date. When the investment still has a value, a last update
+// withdraws the final value, or the last known value when no final value is given, so the gain is realized.
+func (s InvestmentService) Close(investment domain.Investment, date time.Time, finalValue *int64) (domain.Investment, error) {
+	if investment.Locked {
+		return domain.Investment{}, domain.ErrInvestmentIsLocked
+	}
+	if investment.IsClosed() {
+		return domain.Investment{}, domain.ErrInvestmentIsClosed
+	}
+	if investment.LastUpdate != nil && date.Before(investment.LastUpdate.Date) {
+		return domain.Investment{}, errors.Wrap(domain.ErrInvalidInvestment, "closing date is before the last update")
+	}
+
+	withdrawal := finalValue
+	if withdrawal == nil && investment.LastUpdate != nil && investment.LastUpdate.Value != 0 {
+		withdrawal = &investment.LastUpdate.Value
+	}
+	// the closing update and the closing date are written in a single unit of work, so the withdrawal is not booked on
+	// an investment that stays open
+	err := s.unitOfWork.Do(func(repositories Repositories) error {
+		// an update on the closing date takes the withdrawal instead, as there can only be one update per date
+		if withdrawal != nil && investment.LastUpdate != nil && investment.LastUpdate.Date.Equal(date) {
+			lastUpdate := investment.LastUpdate
+			err := repositories.InvestmentUpdate.Update(lastUpdate.ID, deriveValueOrPrice(domain.NewCreateInvestmentUpdateCommand(
+				investment,
+				date,
+				lastUpdate.Deposit,
+				pointer.Of(pointer.GetOrDefault(lastUpdate.Withdrawal, 0)+*withdrawal),
+				lastUpdate.Income,
+				lastUpdate.Fee,
+				0,
+				nil,
+				nil,
+			)))
+			if err != nil {
+				return fmt.Errorf("failed to update closing update: %w", err)
+			}
+		} else if withdrawal != nil {
+			_, err := repositories.InvestmentUpdate.Create(deriveValueOrPrice(domain.NewCreateInvestmentUpdateCommand(
+				investment,
+				date,
+				nil,
+				withdrawal,
+				nil,
+				nil,
+				0,
+				nil,
+				nil,
+			)))
+			if err != nil {
+				return fmt.Errorf("failed to create closing update: %w", err)
+			}
+		}
+
+		err := repositories.Investment.UpdateClosedAt(investment.ID, &date)
+		if err != nil {
+			return fmt.Errorf("failed to close investment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Investment{}, err
+	}
+
+	return s.investmentRepository.FindByID(investment.ID)
+}
+
+// Reopen makes a closed investment active again. The closing update is kept.
+func (s InvestmentService) Reopen(investment domain.Investment, user domain.User) (domain.Investment, error) {
+	if !investment.IsClosed() {
+		return investment, nil
+	}
+
+	investments, err := s.FindActiveByUserID(investment.UserID)
+	if err != nil {
+		return domain.Investment{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+	if user.AccountType == domain.AccountTypeBasic && len(investments) >= MaxInvestmentsForBasicAccount {
+		return domain.Investment{}, domain.ErrMaxInvestmentsReached
+	}
+
+	err = s.investmentRepository.UpdateClosedAt(investment.ID, nil)
+	if err != nil {
+		return domain.Investment{}, fmt.Errorf("failed to reopen investment: %w", err)
+	}
+
+	return s.investmentRepository.FindByID(investment.ID)
+}
+
+// move puts the investment at the given position among the investments of its portfolio and renumbers the others.
+func move(repositories Repositories, investment domain.Investment, displayOrder int) error {
+	investments, err := repositories.Investment.FindByPortfolioID(investment.PortfolioID)
+	if err != nil {
+		return fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	others := make([]domain.Investment, 0)
+	for _, other := range investments {
+		if other.ID != investment.ID {
+			others = append(others, other)
+		}
+	}
+
+	position := max(0, min(displayOrder, len(others)))
+	ordered := append(others[:position:position], append([]domain.Investment{investment}, others[position:]...)...)
+
+	displayOrderByID := make(map[string]int)
+	for i, other := range ordered {
+		displayOrderByID[other.ID] = i
+	}
+	return repositories.Investment.UpdateDisplayOrders(displayOrderByID)
+}
+
+// DeleteByID deletes the investment together with its updates and transactions in a single unit of work.
 func (s InvestmentService) DeleteByID(id string) error {
-	err := s.investmentUpdateService.DeleteByInvestmentID(id)
+	return s.unitOfWork.Do(func(repositories Repositories) error {
+		return deleteInvestment(repositories, id)
+	})
+}
+
+func deleteInvestment(repositories Repositories, id string) error {
+	err := repositories.InvestmentUpdate.DeleteByInvestmentID(id)
 	if err != nil {
 		return errors.Wrapf(err, "failed to delete updates by investment id %s", id)
 	}
 
-	return s.investmentRepository.DeleteByID(id)
+	err = repositories.Transaction.DeleteByInvestmentID(id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete transactions by investment id %s", id)
+	}
+
+	return repositories.Investment.DeleteByID(id)
 }
@@ -3,30 +3,39 @@ package services
 import (
 	"fmt"
 	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"math"
 	"sort"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 type InvestmentUpdateRepository interface {
 	FindByID(id string) (domain.InvestmentUpdate, error)
 	FindByInvestmentID(investmentID string) ([]domain.InvestmentUpdate, error)
 	Find(query domain.FindInvestmentUpdateQuery) ([]domain.InvestmentUpdate, error)
+	FindByInvestmentIDAndDate(investmentID string, date time.Time) (domain.InvestmentUpdate, error)
 	FindLastByInvestmentIDAndDateLessThanEqual(investmentID string, date time.Time) (domain.InvestmentUpdate, error)
 
 	Create(command domain.CreateInvestmentUpdateCommand) (domain.InvestmentUpdate, error)
+	Update(id string, command domain.CreateInvestmentUpdateCommand) error
 	DeleteByInvestmentID(investmentID string) error
 	DeleteByID(id string) error
 }
 
 type InvestmentUpdateService struct {
 	investmentUpdateRepository InvestmentUpdateRepository
+	unitOfWork                 UnitOfWork
 }
 
 func NewInvestmentUpdateService(
 	investmentUpdateRepository InvestmentUpdateRepository,
+	unitOfWork UnitOfWork,
 ) InvestmentUpdateService {
 	return InvestmentUpdateService{
 		investmentUpdateRepository: investmentUpdateRepository,
+		unitOfWork:                 unitOfWork,
 	}
 }
 
@@ -55,8 +64,13 @@ func (s InvestmentUpdateService) Find(query domain.FindInvestmentUpdateQuery) ([
 					*query.DateFrom,
 					nil,
 					nil,
+					nil,
+					nil,
 					lastUpdate.Cost,
+					lastUpdate.Fees,
 					lastUpdate.Value,
+					lastUpdate.Units,
+					lastUpdate.Price,
 				))
 			}
 		}
@@ -86,8 +100,141 @@ func (s InvestmentUpdateService) Create(command domain.CreateInvestmentUpdateCom
 	if command.Investment.Locked {
 		return domain.InvestmentUpdate{}, domain.ErrInvestmentIsLocked
 	}
+	if command.Investment.IsClosed() {
+		return domain.InvestmentUpdate{}, domain.ErrInvestmentIsClosed
+	}
+
+	return s.investmentUpdateRepository.Create(deriveValueOrPrice(command))
+}
+
+// CreateAll creates the updates in a single unit of work, so either all of them are created or none are. Nothing is
+// created when any of the investments is locked or closed.
+func (s InvestmentUpdateService) CreateAll(commands []domain.CreateInvestmentUpdateCommand) ([]domain.InvestmentUpdate, error) {
+	for _, command := range commands {
+		if command.Investment.Locked {
+			return []domain.InvestmentUpdate{}, domain.ErrInvestmentIsLocked
+		}
+		if command.Investment.IsClosed() {
+			return []domain.InvestmentUpdate{}, domain.ErrInvestmentIsClosed
+		}
+	}
 
-	return s.investmentUpdateRepository.Create(command)
+	updates := make([]domain.InvestmentUpdate, 0)
+	err := s.unitOfWork.Do(func(repositories Repositories) error {
+		for _, command := range commands {
+			update, err := repositories.InvestmentUpdate.Create(deriveValueOrPrice(command))
+			if err != nil {
+				return fmt.Errorf("failed to create update: %w", err)
+			}
+			updates = append(updates, update)
+		}
+		return nil
+	})
+	if err != nil {
+		return []domain.InvestmentUpdate{}, err
+	}
+
+	return updates, nil
+}
+
+// Import creates the updates in a single unit of work. An update on a date that already has one is skipped or
+// overwrites the existing one, depending on the merge strategy, or fails the whole import with
+// ErrInvestmentUpdateExists.
+func (s InvestmentUpdateService) Import(
+	commands []domain.CreateInvestmentUpdateCommand,
+	strategy domain.MergeStrategy,
+) (domain.ImportResult, error) {
+	for _, command := range commands {
+		if command.Investment.Locked {
+			return domain.ImportResult{}, domain.ErrInvestmentIsLocked
+		}
+		if command.Investment.IsClosed() {
+			return domain.ImportResult{}, domain.ErrInvestmentIsClosed
+		}
+	}
+
+	var result domain.ImportResult
+	err := s.unitOfWork.Do(func(repositories Repositories) error {
+		result = domain.ImportResult{}
+		for _, command := range commands {
+			existing, err := repositories.InvestmentUpdate.FindByInvestmentIDAndDate(command.Investment.ID, command.Date)
+			if err != nil && err != domain.ErrInvestmentUpdateNotFound {
+				return fmt.Errorf("failed to find existing update: %w", err)
+			}
+
+			if err == domain.ErrInvestmentUpdateNotFound {
+				_, err := repositories.InvestmentUpdate.Create(deriveValueOrPrice(command))
+				if err != nil {
+					return fmt.Errorf("failed to create update: %w", err)
+				}
+				result.Inserted++
+				continue
+			}
+
+			switch strategy {
+			case domain.MergeStrategySkip:
+				result.Skipped++
+			case domain.MergeStrategyOverwrite:
+				err := repositories.InvestmentUpdate.Update(existing.ID, deriveValueOrPrice(command))
+				if err != nil {
+					return fmt.Errorf("failed to update update: %w", err)
+				}
+				result.Updated++
+			default:
+				return errors.Wrapf(domain.ErrInvestmentUpdateExists, "on %s", command.Date.Format("2006-01-02"))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	return result, nil
+}
+
+// Update replaces the update with the command and returns it together with every later update of the investment,
+// because the cost of those depends on the edited one.
+func (s InvestmentUpdateService) Update(
+	update domain.InvestmentUpdate,
+	command domain.CreateInvestmentUpdateCommand,
+) ([]domain.InvestmentUpdate, error) {
+	if command.Investment.Locked {
+		return []domain.InvestmentUpdate{}, domain.ErrInvestmentIsLocked
+	}
+	if command.Investment.IsClosed() {
+		return []domain.InvestmentUpdate{}, domain.ErrInvestmentIsClosed
+	}
+
+	err := s.investmentUpdateRepository.Update(update.ID, deriveValueOrPrice(command))
+	if err != nil {
+		return []domain.InvestmentUpdate{}, fmt.Errorf("failed to update investment update: %w", err)
+	}
+
+	dateFrom := update.Date
+	if command.Date.Before(dateFrom) {
+		dateFrom = command.Date
+	}
+
+	updates, err := s.investmentUpdateRepository.Find(domain.FindInvestmentUpdateQuery{
+		InvestmentIDs: []string{update.InvestmentID},
+		DateFrom:      &dateFrom,
+	})
+	if err != nil {
+		return []domain.InvestmentUpdate{}, fmt.Errorf("failed to find updates: %w", err)
+	}
+
+	return updates, nil
+}
+
+// deriveValueOrPrice derives the value from the units and the price, or the price from the units and the value.
+func deriveValueOrPrice(command domain.CreateInvestmentUpdateCommand) domain.CreateInvestmentUpdateCommand {
+	if command.Units != nil && command.Price != nil {
+		command.Value = int64(math.Round(*command.Units * *command.Price))
+	} else if command.Units != nil && *command.Units != 0 {
+		command.Price = pointer.Of(float64(command.Value) / *command.Units)
+	}
+	return command
 }
 
 func (s InvestmentUpdateService) DeleteByInvestmentID(investmentID string) error {
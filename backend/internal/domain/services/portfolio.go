@@ -0,0 +1,521 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type PortfolioRepository interface {
+	FindByID(id string) (domain.Portfolio, error)
+	FindByUserID(userID string) ([]domain.Portfolio, error)
+
+	Create(userID, name string, currency domain.Currency) (domain.Portfolio, error)
+	Update(id, name string, currency domain.Currency) (domain.Portfolio, error)
+	DeleteByID(id string) error
+	DeleteByUserID(userID string) error
+}
+
+type PortfolioService struct {
+	portfolioRepository     PortfolioRepository
+	investmentUpdateService InvestmentUpdateService
+	fxRateService           FXRateService
+	settingsService         SettingsService
+	unitOfWork              UnitOfWork
+}
+
+func NewPortfolioService(
+	portfolioRepository PortfolioRepository,
+	investmentUpdateService InvestmentUpdateService,
+	fxRateService FXRateService,
+	settingsService SettingsService,
+	unitOfWork UnitOfWork,
+) PortfolioService {
+	return PortfolioService{
+		portfolioRepository:     portfolioRepository,
+		investmentUpdateService: investmentUpdateService,
+		fxRateService:           fxRateService,
+		settingsService:         settingsService,
+		unitOfWork:              unitOfWork,
+	}
+}
+
+func (s PortfolioService) FindByID(id string) (domain.Portfolio, error) {
+	return s.portfolioRepository.FindByID(id)
+}
+
+func (s PortfolioService) FindByUserID(userID string) ([]domain.Portfolio, error) {
+	return s.portfolioRepository.FindByUserID(userID)
+}
+
+// FindDefaultByUserID finds the first portfolio of the user, which is used when no portfolio is selected.
+func (s PortfolioService) FindDefaultByUserID(userID string) (domain.Portfolio, error) {
+	portfolios, err := s.portfolioRepository.FindByUserID(userID)
+	if err != nil {
+		return domain.Portfolio{}, fmt.Errorf("failed to find portfolios: %w", err)
+	}
+	if len(portfolios) == 0 {
+		return domain.Portfolio{}, domain.ErrPortfolioNotFound
+	}
+
+	return portfolios[0], nil
+}
+
+// Create creates a portfolio for the user. Without a currency, the portfolio takes the currency of the settings of
+// the user.
+func (s PortfolioService) Create(userID, name string, currency domain.Currency) (domain.Portfolio, error) {
+	name, err := s.validateName(userID, "", name)
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+
+	if currency == "" {
+		settings, err := s.settingsService.FindByUserID(userID)
+		if err != nil {
+			return domain.Portfolio{}, fmt.Errorf("failed to find settings: %w", err)
+		}
+		currency = settings.Currency
+	}
+
+	return s.portfolioRepository.Create(userID, name, currency)
+}
+
+// createDefault creates the first portfolio of a new user in the currency of the settings of the user, with the
+// repositories of the unit of work that creates the user.
+func (s PortfolioService) createDefault(repositories Repositories, userID string) (domain.Portfolio, error) {
+	settings, err := s.settingsService.FindByUserID(userID)
+	if err != nil {
+		return domain.Portfolio{}, fmt.Errorf("failed to find settings: %w", err)
+	}
+
+	return repositories.Portfolio.Create(userID, domain.DefaultPortfolioName, settings.Currency)
+}
+
+func (s PortfolioService) Update(
+	portfolio domain.Portfolio,
+	command domain.UpdatePortfolioCommand,
+) (domain.Portfolio, error) {
+	name := portfolio.Name
+	if command.Name != nil {
+		validated, err := s.validateName(portfolio.UserID, portfolio.ID, *command.Name)
+		if err != nil {
+			return domain.Portfolio{}, err
+		}
+		name = validated
+	}
+	currency := portfolio.Currency
+	if command.Currency != nil {
+		currency = *command.Currency
+	}
+
+	return s.portfolioRepository.Update(portfolio.ID, name, currency)
+}
+
+// DeleteByID deletes the portfolio together with its investments in a single unit of work. The last portfolio of a
+// user cannot be deleted.
+func (s PortfolioService) DeleteByID(portfolio domain.Portfolio) error {
+	portfolios, err := s.portfolioRepository.FindByUserID(portfolio.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find portfolios: %w", err)
+	}
+	if len(portfolios) <= 1 {
+		return errors.Wrap(domain.ErrInvalidPortfolio, "the last portfolio cannot be deleted")
+	}
+
+	return s.unitOfWork.Do(func(repositories Repositories) error {
+		investments, err := repositories.Investment.FindByPortfolioID(portfolio.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find investments by portfolio id %s", portfolio.ID)
+		}
+
+		for _, investment := range investments {
+			err := deleteInvestment(repositories, investment.ID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to delete investment by id %s", investment.ID)
+			}
+		}
+
+		return repositories.Portfolio.DeleteByID(portfolio.ID)
+	})
+}
+
+func (s PortfolioService) DeleteByUserID(userID string) error {
+	return s.portfolioRepository.DeleteByUserID(userID)
+}
+
+func (s PortfolioService) validateName(userID, portfolioID, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.Wrap(domain.ErrInvalidPortfolio, "name cannot be empty")
+	}
+
+	portfolios, err := s.portfolioRepository.FindByUserID(userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find portfolios: %w", err)
+	}
+	for _, portfolio := range portfolios {
+		if portfolio.ID != portfolioID && strings.EqualFold(portfolio.Name, name) {
+			return "", errors.Wrapf(domain.ErrInvalidPortfolio, "portfolio %q already exists", name)
+		}
+	}
+
+	return name, nil
+}
+
+// FindHistory resamples the updates into one point per interval. Every point is dated at the end of its interval and
+// sums the last known cost and value of each investment on that date, converted at the rate of that date.
+func (s PortfolioService) FindHistory(query domain.FindPortfolioHistoryQuery) ([]domain.PortfolioHistoryPoint, error) {
+	updates, err := s.investmentUpdateService.Find(domain.FindInvestmentUpdateQuery{
+		InvestmentIDs: slices.Map(query.Investments, func(i domain.Investment) string { return i.ID }),
+		DateTo:        query.DateTo,
+	})
+	if err != nil {
+		return []domain.PortfolioHistoryPoint{}, fmt.Errorf("failed to find updates: %w", err)
+	}
+
+	rates, err := s.fxRateService.FindRates(query.Investments, query.Currency)
+	if err != nil {
+		return []domain.PortfolioHistoryPoint{}, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	points := make([]domain.PortfolioHistoryPoint, 0)
+	err = resample(updates, query.Interval, query.DateFrom, query.DateTo, func(date time.Time, lastUpdateByInvestmentID map[string]domain.InvestmentUpdate) error {
+		converted, err := convertLastUpdates(rates, query.Investments, lastUpdateByInvestmentID, query.Currency, date)
+		if err != nil {
+			return err
+		}
+
+		var cost, value int64
+		for _, lastUpdate := range converted {
+			if query.ExcludeFeesFromCost {
+				cost += lastUpdate.CostExcludingFees()
+			} else {
+				cost += lastUpdate.Cost
+			}
+			value += lastUpdate.Value
+		}
+		points = append(points, domain.NewPortfolioHistoryPoint(date, cost, value))
+		return nil
+	})
+	if err != nil {
+		return []domain.PortfolioHistoryPoint{}, fmt.Errorf("failed to resample updates: %w", err)
+	}
+
+	return points, nil
+}
+
+// FindAllocation groups the value of the investments by type, using the last update of each investment on or before
+// asOf.
+func (s PortfolioService) FindAllocation(
+	investments []domain.Investment,
+	currency domain.Currency,
+	asOf time.Time,
+) (domain.Allocation, error) {
+	converted, err := s.findConvertedLastUpdates(investments, currency, asOf)
+	if err != nil {
+		return domain.Allocation{}, err
+	}
+
+	return toAllocation(asOf, investments, converted), nil
+}
+
+// FindAllocationByTag sums the value of the investments in every tag group, using the last update of each investment
+// on or before asOf. The percentages are of the value of all investments, so they add up to more than 100 when
+// investments have more than one tag.
+func (s PortfolioService) FindAllocationByTag(
+	investments []domain.Investment,
+	groups []domain.TagGroup,
+	currency domain.Currency,
+	asOf time.Time,
+) (domain.TagAllocation, error) {
+	converted, err := s.findConvertedLastUpdates(investments, currency, asOf)
+	if err != nil {
+		return domain.TagAllocation{}, err
+	}
+
+	var total int64
+	for _, lastUpdate := range converted {
+		total += lastUpdate.Value
+	}
+
+	entries := make([]domain.TagAllocationEntry, 0)
+	for _, group := range groups {
+		var value int64
+		for _, investment := range group.Investments {
+			value += converted[investment.ID].Value
+		}
+
+		var percentage float64
+		if total != 0 {
+			percentage = float64(value) / float64(total) * 100
+		}
+		entries = append(entries, domain.NewTagAllocationEntry(group.Tag, value, percentage))
+	}
+
+	return domain.NewTagAllocation(asOf, total, entries), nil
+}
+
+func (s PortfolioService) findConvertedLastUpdates(
+	investments []domain.Investment,
+	currency domain.Currency,
+	asOf time.Time,
+) (map[string]domain.InvestmentUpdate, error) {
+	lastUpdateByInvestmentID := make(map[string]domain.InvestmentUpdate)
+	for _, investment := range investments {
+		lastUpdate, err := s.investmentUpdateService.FindLastByInvestmentIDAndDateLessThanEqual(investment.ID, asOf)
+		if err != nil {
+			if err == domain.ErrInvestmentUpdateNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to find last update: %w", err)
+		}
+		lastUpdateByInvestmentID[investment.ID] = lastUpdate
+	}
+
+	rates, err := s.fxRateService.FindRates(investments, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	converted, err := convertLastUpdates(rates, investments, lastUpdateByInvestmentID, currency, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert updates: %w", err)
+	}
+
+	return converted, nil
+}
+
+// FindAllocationHistory resamples the allocation by type into one allocation per interval, so drift in the mix
+// becomes visible.
+func (s PortfolioService) FindAllocationHistory(query domain.FindAllocationHistoryQuery) ([]domain.Allocation, error) {
+	updates, err := s.investmentUpdateService.Find(domain.FindInvestmentUpdateQuery{
+		InvestmentIDs: slices.Map(query.Investments, func(i domain.Investment) string { return i.ID }),
+		DateTo:        query.DateTo,
+	})
+	if err != nil {
+		return []domain.Allocation{}, fmt.Errorf("failed to find updates: %w", err)
+	}
+
+	rates, err := s.fxRateService.FindRates(query.Investments, query.Currency)
+	if err != nil {
+		return []domain.Allocation{}, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	allocations := make([]domain.Allocation, 0)
+	err = resample(updates, query.Interval, query.DateFrom, query.DateTo, func(date time.Time, lastUpdateByInvestmentID map[string]domain.InvestmentUpdate) error {
+		converted, err := convertLastUpdates(rates, query.Investments, lastUpdateByInvestmentID, query.Currency, date)
+		if err != nil {
+			return err
+		}
+
+		allocations = append(allocations, toAllocation(date, query.Investments, converted))
+		return nil
+	})
+	if err != nil {
+		return []domain.Allocation{}, fmt.Errorf("failed to resample updates: %w", err)
+	}
+
+	return allocations, nil
+}
+
+func toAllocation(
+	asOf time.Time,
+	investments []domain.Investment,
+	lastUpdateByInvestmentID map[string]domain.InvestmentUpdate,
+) domain.Allocation {
+	types := make([]domain.InvestmentType, 0)
+	valueByType := make(map[domain.InvestmentType]int64)
+	var total int64
+	for _, investment := range investments {
+		if _, ok := valueByType[investment.Type]; !ok {
+			types = append(types, investment.Type)
+		}
+		value := lastUpdateByInvestmentID[investment.ID].Value
+		valueByType[investment.Type] += value
+		total += value
+	}
+
+	entries := make([]domain.AllocationEntry, 0)
+	for _, t := range types {
+		var percentage float64
+		if total != 0 {
+			percentage = float64(valueByType[t]) / float64(total) * 100
+		}
+		entries = append(entries, domain.NewAllocationEntry(t, valueByType[t], percentage))
+	}
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].Value > entries[b].Value })
+
+	return domain.NewAllocation(asOf, total, entries)
+}
+
+// FindIncome sums the income of the updates between DateFrom and DateTo. Every amount is converted at the rate on the
+// date of its update.
+func (s PortfolioService) FindIncome(query domain.FindIncomeQuery) (domain.Income, error) {
+	updates, err := s.investmentUpdateService.Find(domain.FindInvestmentUpdateQuery{
+		InvestmentIDs: slices.Map(query.Investments, func(i domain.Investment) string { return i.ID }),
+		DateFrom:      query.DateFrom,
+		DateTo:        query.DateTo,
+	})
+	if err != nil {
+		return domain.Income{}, fmt.Errorf("failed to find updates: %w", err)
+	}
+
+	rates, err := s.fxRateService.FindRates(query.Investments, query.Currency)
+	if err != nil {
+		return domain.Income{}, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	currencies := currencyByInvestmentID(query.Investments)
+
+	var total int64
+	byMonth := make(map[time.Time]int64)
+	byYear := make(map[time.Time]int64)
+	byInvestmentID := make(map[string]int64)
+	for _, update := range updates {
+		if update.Income == nil {
+			continue
+		}
+
+		income, err := rates.Convert(*update.Income, currencies[update.InvestmentID], query.Currency, update.Date)
+		if err != nil {
+			return domain.Income{}, fmt.Errorf("failed to convert income: %w", err)
+		}
+
+		total += income
+		byMonth[startOfInterval(update.Date, domain.IntervalMonth)] += income
+		byYear[startOfInterval(update.Date, domain.IntervalYear)] += income
+		byInvestmentID[update.InvestmentID] += income
+	}
+
+	byInvestment := make([]domain.InvestmentIncome, 0)
+	for _, investment := range query.Investments {
+		if amount, ok := byInvestmentID[investment.ID]; ok {
+			byInvestment = append(byInvestment, domain.NewInvestmentIncome(investment.ID, amount))
+		}
+	}
+
+	return domain.NewIncome(total, toIncomePeriods(byMonth), toIncomePeriods(byYear), byInvestment), nil
+}
+
+func toIncomePeriods(amountByDate map[time.Time]int64) []domain.IncomePeriod {
+	periods := make([]domain.IncomePeriod, 0)
+	for date, amount := range amountByDate {
+		periods = append(periods, domain.NewIncomePeriod(date, amount))
+	}
+	sort.Slice(periods, func(a, b int) bool { return periods[a].Date.Before(periods[b].Date) })
+	return periods
+}
+
+// convertLastUpdates converts the last update of every investment to the reporting currency at the rate of date.
+func convertLastUpdates(
+	rates domain.FXRates,
+	investments []domain.Investment,
+	lastUpdateByInvestmentID map[string]domain.InvestmentUpdate,
+	currency domain.Currency,
+	date time.Time,
+) (map[string]domain.InvestmentUpdate, error) {
+	currencies := currencyByInvestmentID(investments)
+
+	converted := make(map[string]domain.InvestmentUpdate)
+	for investmentID, lastUpdate := range lastUpdateByInvestmentID {
+		convertedUpdate, err := convertUpdate(rates, lastUpdate, currencies[investmentID], currency, date)
+		if err != nil {
+			return nil, err
+		}
+		converted[investmentID] = convertedUpdate
+	}
+	return converted, nil
+}
+
+// resample walks through the intervals between dateFrom and dateTo and calls f at the end of each interval with the
+// last update of every investment on or before that date. The history starts at the first update at the earliest and
+// cannot have more than domain.MaxHistoryPoints intervals. The updates must be sorted by date.
+func resample(
+	updates []domain.InvestmentUpdate,
+	interval domain.Interval,
+	dateFrom *time.Time,
+	dateTo *time.Time,
+	f func(date time.Time, lastUpdateByInvestmentID map[string]domain.InvestmentUpdate) error,
+) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	from := updates[0].Date
+	if dateFrom != nil && dateFrom.After(from) {
+		from = *dateFrom
+	}
+	to := today()
+	if dateTo != nil {
+		to = *dateTo
+	}
+
+	points := 0
+	for start := startOfInterval(from, interval); !start.After(to); start = addInterval(start, interval) {
+		points++
+		if points > domain.MaxHistoryPoints {
+			return errors.Wrapf(domain.ErrTooManyHistoryPoints, "more than %d intervals, choose a longer interval or a shorter range", domain.MaxHistoryPoints)
+		}
+	}
+
+	lastUpdateByInvestmentID := make(map[string]domain.InvestmentUpdate)
+	next := 0
+	for start := startOfInterval(from, interval); !start.After(to); start = addInterval(start, interval) {
+		end := addInterval(start, interval).AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+
+		for ; next < len(updates) && !updates[next].Date.After(end); next++ {
+			lastUpdateByInvestmentID[updates[next].InvestmentID] = updates[next]
+		}
+
+		err := f(end, lastUpdateByInvestmentID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func startOfInterval(date time.Time, interval domain.Interval) time.Time {
+	year, month, day := date.Date()
+	switch interval {
+	case domain.IntervalWeek:
+		daysSinceMonday := (int(date.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, date.Location())
+	case domain.IntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, date.Location())
+	case domain.IntervalQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, date.Location())
+	case domain.IntervalYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, date.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	}
+}
+
+func addInterval(date time.Time, interval domain.Interval) time.Time {
+	switch interval {
+	case domain.IntervalWeek:
+		return date.AddDate(0, 0, 7)
+	case domain.IntervalMonth:
+		return date.AddDate(0, 1, 0)
+	case domain.IntervalQuarter:
+		return date.AddDate(0, 3, 0)
+	case domain.IntervalYear:
+		return date.AddDate(1, 0, 0)
+	default:
+		return date.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"growfolio/internal/slices"
+	"sort"
+	"time"
+)
+
+type ReturnService struct {
+	investmentUpdateService InvestmentUpdateService
+	fxRateService           FXRateService
+}
+
+func NewReturnService(investmentUpdateService InvestmentUpdateService, fxRateService FXRateService) ReturnService {
+	return ReturnService{
+		investmentUpdateService: investmentUpdateService,
+		fxRateService:           fxRateService,
+	}
+}
+
+// FindTimeWeightedReturns chains the sub-period returns between consecutive updates, so deposits and withdrawals
+// do not count as gains or losses. The last update on or before DateFrom is used as the starting value. Investments
+// are measured in their own currency and the portfolio in the reporting currency of the query.
+func (s ReturnService) FindTimeWeightedReturns(query domain.FindReturnsQuery) (domain.TimeWeightedReturns, error) {
+	updates, err := s.findUpdates(query)
+	if err != nil {
+		return domain.TimeWeightedReturns{}, err
+	}
+
+	updatesByInvestmentID := make(map[string][]domain.InvestmentUpdate)
+	for _, update := range updates {
+		updatesByInvestmentID[update.InvestmentID] = append(updatesByInvestmentID[update.InvestmentID], update)
+	}
+
+	byInvestmentID := make(map[string]float64)
+	for _, investment := range query.Investments {
+		points := toValuePoints(updatesByInvestmentID[investment.ID])
+		byInvestmentID[investment.ID] = chainTimeWeightedReturn(points, query.DateFrom, query.DateTo)
+	}
+
+	converted, err := s.convertUpdates(updates, query)
+	if err != nil {
+		return domain.TimeWeightedReturns{}, err
+	}
+
+	portfolio := chainTimeWeightedReturn(toValuePoints(converted), query.DateFrom, query.DateTo)
+
+	return domain.NewTimeWeightedReturns(portfolio, byInvestmentID), nil
+}
+
+// FindReturns calculates the time-weighted and the money-weighted return of the investments taken together. The
+// money-weighted return is the XIRR of the deposits, withdrawals and paid out income, with the latest value as the
+// terminal flow.
+func (s ReturnService) FindReturns(query domain.FindReturnsQuery) (domain.Returns, error) {
+	updates, err := s.findUpdates(query)
+	if err != nil {
+		return domain.Returns{}, err
+	}
+
+	converted, err := s.convertUpdates(updates, query)
+	if err != nil {
+		return domain.Returns{}, err
+	}
+
+	points := toValuePoints(converted)
+
+	moneyWeightedReturn, err := XIRR(toCashFlows(points, query.DateFrom, query.DateTo))
+	if err != nil {
+		return domain.Returns{}, fmt.Errorf("failed to calculate XIRR: %w", err)
+	}
+
+	return domain.NewReturns(chainTimeWeightedReturn(points, query.DateFrom, query.DateTo), moneyWeightedReturn), nil
+}
+
+func (s ReturnService) findUpdates(query domain.FindReturnsQuery) ([]domain.InvestmentUpdate, error) {
+	updates, err := s.investmentUpdateService.Find(domain.FindInvestmentUpdateQuery{
+		InvestmentIDs: slices.Map(query.Investments, func(i domain.Investment) string { return i.ID }),
+		DateTo:        query.DateTo,
+	})
+	if err != nil {
+		return []domain.InvestmentUpdate{}, fmt.Errorf("failed to find updates: %w", err)
+	}
+	return updates, nil
+}
+
+// convertUpdates converts every update to the reporting currency at the rate on the date of the update.
+func (s ReturnService) convertUpdates(
+	updates []domain.InvestmentUpdate,
+	query domain.FindReturnsQuery,
+) ([]domain.InvestmentUpdate, error) {
+	rates, err := s.fxRateService.FindRates(query.Investments, query.Currency)
+	if err != nil {
+		return []domain.InvestmentUpdate{}, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	currencies := currencyByInvestmentID(query.Investments)
+	converted := make([]domain.InvestmentUpdate, 0)
+	for _, update := range updates {
+		convertedUpdate, err := convertUpdate(rates, update, currencies[update.InvestmentID], query.Currency, update.Date)
+		if err != nil {
+			return []domain.InvestmentUpdate{}, fmt.Errorf("failed to convert update: %w", err)
+		}
+		converted = append(converted, convertedUpdate)
+	}
+	return converted, nil
+}
+
+// valuePoint is the combined value of one or more investments at the end of a date, together with the external cash
+// flow that happened on that date. Income that is paid out leaves as a negative flow, so it counts as return.
+type valuePoint struct {
+	Date  time.Time
+	Flow  int64
+	Value int64
+}
+
+// toValuePoints merges updates of one or more investments into a value point per date. The last known value of every
+// investment is carried forward. An investment that appears for the first time brings in its whole value as a flow.
+func toValuePoints(updates []domain.InvestmentUpdate) []valuePoint {
+	sorted := make([]domain.InvestmentUpdate, len(updates))
+	copy(sorted, updates)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Date.Before(sorted[b].Date) })
+
+	points := make([]valuePoint, 0)
+	lastValueByInvestmentID := make(map[string]int64)
+
+	for i := 0; i < len(sorted); {
+		date := sorted[i].Date
+
+		var flow int64
+		newInvestmentIDs := make(map[string]bool)
+		for ; i < len(sorted) && sorted[i].Date.Equal(date); i++ {
+			update := sorted[i]
+			if _, seen := lastValueByInvestmentID[update.InvestmentID]; !seen || newInvestmentIDs[update.InvestmentID] {
+				newInvestmentIDs[update.InvestmentID] = true
+			} else {
+				flow += pointer.GetOrDefault(update.Deposit, 0) -
+					pointer.GetOrDefault(update.Withdrawal, 0) -
+					pointer.GetOrDefault(update.Income, 0)
+			}
+			lastValueByInvestmentID[update.InvestmentID] = update.Value
+		}
+
+		for investmentID := range newInvestmentIDs {
+			flow += lastValueByInvestmentID[investmentID]
+		}
+
+		var value int64
+		for _, lastValue := range lastValueByInvestmentID {
+			value += lastValue
+		}
+
+		points = append(points, valuePoint{Date: date, Flow: flow, Value: value})
+	}
+
+	return points
+}
+
+// chainTimeWeightedReturn links the returns of the sub-periods between consecutive points. A sub-period that starts
+// without any value has no return and is skipped.
+func chainTimeWeightedReturn(points []valuePoint, dateFrom, dateTo *time.Time) float64 {
+	growth := 1.0
+
+	var previous *valuePoint
+	for i := range points {
+		point := points[i]
+		if dateTo != nil && point.Date.After(*dateTo) {
+			break
+		}
+		if dateFrom != nil && !point.Date.After(*dateFrom) {
+			previous = &points[i]
+			continue
+		}
+		if previous != nil && previous.Value > 0 {
+			growth *= float64(point.Value-point.Flow) / float64(previous.Value)
+		}
+		previous = &points[i]
+	}
+
+	return growth - 1
+}
+
+// toCashFlows turns the value points into cash flows for the XIRR. The value on or before dateFrom is invested at
+// dateFrom and the last value on or before dateTo is taken out at the date it was recorded.
+func toCashFlows(points []valuePoint, dateFrom, dateTo *time.Time) []domain.CashFlow {
+	cashFlows := make([]domain.CashFlow, 0)
+
+	var start, last *valuePoint
+	for i := range points {
+		point := points[i]
+		if dateTo != nil && point.Date.After(*dateTo) {
+			break
+		}
+		if dateFrom != nil && !point.Date.After(*dateFrom) {
+			start = &points[i]
+			continue
+		}
+		if last == nil && start != nil && start.Value != 0 {
+			cashFlows = append(cashFlows, domain.NewCashFlow(*dateFrom, -start.Value))
+		}
+		if point.Flow != 0 {
+			cashFlows = append(cashFlows, domain.NewCashFlow(point.Date, -point.Flow))
+		}
+		last = &points[i]
+	}
+
+	if last != nil && last.Value != 0 {
+		cashFlows = append(cashFlows, domain.NewCashFlow(last.Date, last.Value))
+	}
+
+	return cashFlows
+}
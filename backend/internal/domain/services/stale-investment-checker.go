@@ -0,0 +1,58 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+type StaleInvestmentChecker struct {
+	investmentService InvestmentService
+	settingsService   SettingsService
+}
+
+func NewStaleInvestmentChecker(
+	investmentService InvestmentService,
+	settingsService SettingsService,
+) StaleInvestmentChecker {
+	return StaleInvestmentChecker{
+		investmentService: investmentService,
+		settingsService:   settingsService,
+	}
+}
+
+func (c StaleInvestmentChecker) Check() {
+	slog.Info("Checking stale investments...")
+
+	investments, err := c.investmentService.FindAllActive()
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to find investments: %+v", err))
+		return
+	}
+
+	date := today()
+	staleAfterDaysByUserID := make(map[string]int)
+	reminded := 0
+	for _, investment := range investments {
+		staleAfterDays, ok := staleAfterDaysByUserID[investment.UserID]
+		if !ok {
+			settings, err := c.settingsService.FindByUserID(investment.UserID)
+			if err != nil {
+				slog.Error(fmt.Sprintf("failed to find settings of user %s: %+v", investment.UserID, err))
+				continue
+			}
+			staleAfterDays = settings.StaleAfterDays
+			staleAfterDaysByUserID[investment.UserID] = staleAfterDays
+		}
+
+		ok, err := c.investmentService.RemindIfStale(investment, staleAfterDays, date)
+		if err != nil {
+			slog.Error(fmt.Sprintf("failed to remind about stale investment %s: %+v", investment.ID, err))
+			continue
+		}
+		if ok {
+			reminded++
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Reminded about %d stale investments", reminded))
+}
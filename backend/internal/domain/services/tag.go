@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type TagRepository interface {
+	FindByID(id string) (domain.Tag, error)
+	FindByUserID(userID string) ([]domain.Tag, error)
+
+	Create(userID, name string) (domain.Tag, error)
+	UpdateName(id, name string) (domain.Tag, error)
+	DeleteByID(id string) error
+	DeleteByUserID(userID string) error
+	ReplaceByInvestmentID(investmentID string, tagIDs []string) error
+}
+
+type TagService struct {
+	tagRepository TagRepository
+}
+
+func NewTagService(tagRepository TagRepository) TagService {
+	return TagService{
+		tagRepository: tagRepository,
+	}
+}
+
+func (s TagService) FindByID(id string) (domain.Tag, error) {
+	return s.tagRepository.FindByID(id)
+}
+
+func (s TagService) FindByUserID(userID string) ([]domain.Tag, error) {
+	return s.tagRepository.FindByUserID(userID)
+}
+
+func (s TagService) Create(userID, name string) (domain.Tag, error) {
+	name, err := s.validateName(userID, "", name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return s.tagRepository.Create(userID, name)
+}
+
+func (s TagService) Rename(tag domain.Tag, name string) (domain.Tag, error) {
+	name, err := s.validateName(tag.UserID, tag.ID, name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return s.tagRepository.UpdateName(tag.ID, name)
+}
+
+func (s TagService) DeleteByID(id string) error {
+	return s.tagRepository.DeleteByID(id)
+}
+
+func (s TagService) DeleteByUserID(userID string) error {
+	return s.tagRepository.DeleteByUserID(userID)
+}
+
+// ReplaceInvestmentTags overwrites the tags of the investment. The tags must belong to the owner of the investment.
+func (s TagService) ReplaceInvestmentTags(investment domain.Investment, tagIDs []string) error {
+	tags, err := s.tagRepository.FindByUserID(investment.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find tags: %w", err)
+	}
+
+	tagsByID := slices.AssociateBy(tags, func(t domain.Tag) string { return t.ID })
+	for _, tagID := range tagIDs {
+		if _, ok := tagsByID[tagID]; !ok {
+			return errors.Wrapf(domain.ErrInvalidTag, "unknown tag %s", tagID)
+		}
+	}
+
+	return s.tagRepository.ReplaceByInvestmentID(investment.ID, slices.Deduplicate(tagIDs))
+}
+
+// GroupByTag puts every investment in the group of each of its tags, so an investment with more than one tag is in
+// more than one group. The investments without tags end up in a last group without a tag.
+func (s TagService) GroupByTag(userID string, investments []domain.Investment) ([]domain.TagGroup, error) {
+	tags, err := s.tagRepository.FindByUserID(userID)
+	if err != nil {
+		return []domain.TagGroup{}, fmt.Errorf("failed to find tags: %w", err)
+	}
+
+	groups := make([]domain.TagGroup, 0)
+	for i := range tags {
+		tagged := make([]domain.Investment, 0)
+		for _, investment := range investments {
+			if investment.HasTag(tags[i].ID) {
+				tagged = append(tagged, investment)
+			}
+		}
+		groups = append(groups, domain.NewTagGroup(&tags[i], tagged))
+	}
+
+	untagged := make([]domain.Investment, 0)
+	for _, investment := range investments {
+		if len(investment.TagIDs) == 0 {
+			untagged = append(untagged, investment)
+		}
+	}
+	groups = append(groups, domain.NewTagGroup(nil, untagged))
+
+	return groups, nil
+}
+
+func (s TagService) validateName(userID, tagID, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.Wrap(domain.ErrInvalidTag, "name cannot be empty")
+	}
+
+	tags, err := s.tagRepository.FindByUserID(userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find tags: %w", err)
+	}
+	for _, tag := range tags {
+		if tag.ID != tagID && strings.EqualFold(tag.Name, name) {
+			return "", errors.Wrapf(domain.ErrInvalidTag, "tag %q already exists", name)
+		}
+	}
+
+	return name, nil
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"math"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+type TargetAllocationRepository interface {
+	FindByPortfolioID(portfolioID string) ([]domain.TargetAllocation, error)
+
+	ReplaceByPortfolio(portfolio domain.Portfolio, targets []domain.TargetAllocation) ([]domain.TargetAllocation, error)
+	DeleteByUserID(userID string) error
+}
+
+type TargetAllocationService struct {
+	targetAllocationRepository TargetAllocationRepository
+	investmentService          InvestmentService
+	fxRateService              FXRateService
+}
+
+func NewTargetAllocationService(
+	targetAllocationRepository TargetAllocationRepository,
+	investmentService InvestmentService,
+	fxRateService FXRateService,
+) TargetAllocationService {
+	return TargetAllocationService{
+		targetAllocationRepository: targetAllocationRepository,
+		investmentService:          investmentService,
+		fxRateService:              fxRateService,
+	}
+}
+
+func (s TargetAllocationService) FindByPortfolioID(portfolioID string) ([]domain.TargetAllocation, error) {
+	return s.targetAllocationRepository.FindByPortfolioID(portfolioID)
+}
+
+// Replace overwrites all target allocations of the portfolio. Targets are either all per investment type or all per
+// investment of the portfolio, and their percentages add up to 100.
+func (s TargetAllocationService) Replace(
+	portfolio domain.Portfolio,
+	targets []domain.TargetAllocation,
+) ([]domain.TargetAllocation, error) {
+	investments, err := s.investmentService.FindActiveByPortfolioID(portfolio.ID)
+	if err != nil {
+		return []domain.TargetAllocation{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	err = validateTargetAllocations(targets, investments)
+	if err != nil {
+		return []domain.TargetAllocation{}, err
+	}
+
+	return s.targetAllocationRepository.ReplaceByPortfolio(portfolio, targets)
+}
+
+func (s TargetAllocationService) DeleteByUserID(userID string) error {
+	return s.targetAllocationRepository.DeleteByUserID(userID)
+}
+
+// Rebalance compares the current values of the investments with the targets of the portfolio.
+//
+// Without new money, every investment is brought back to its target as soon as one target is outside the tolerance
+// band, which may require withdrawals. With new money, only deposits are suggested: the underweight investments are
+// topped up first and what is left is spread according to the targets. All amounts are in the currency of the
+// portfolio.
+func (s TargetAllocationService) Rebalance(
+	portfolio domain.Portfolio,
+	tolerance float64,
+	newMoney *int64,
+) (domain.Rebalance, error) {
+	currency := portfolio.Currency
+
+	targets, err := s.targetAllocationRepository.FindByPortfolioID(portfolio.ID)
+	if err != nil {
+		return domain.Rebalance{}, fmt.Errorf("failed to find target allocations: %w", err)
+	}
+	if len(targets) == 0 {
+		return domain.Rebalance{}, domain.ErrTargetAllocationsNotFound
+	}
+
+	investments, err := s.investmentService.FindActiveByPortfolioID(portfolio.ID)
+	if err != nil {
+		return domain.Rebalance{}, fmt.Errorf("failed to find investments: %w", err)
+	}
+
+	// the targets were valid when they were saved, but investments may have been closed or deleted since
+	err = validateTargetAllocations(targets, investments)
+	if err != nil {
+		return domain.Rebalance{}, errors.Wrap(err, "the targets no longer match the investments, save them again")
+	}
+
+	rates, err := s.fxRateService.FindRates(investments, currency)
+	if err != nil {
+		return domain.Rebalance{}, fmt.Errorf("failed to find fx rates: %w", err)
+	}
+
+	for i, investment := range investments {
+		if investment.LastUpdate == nil {
+			continue
+		}
+		converted, err := convertUpdate(rates, *investment.LastUpdate, investment.Currency, currency, today())
+		if err != nil {
+			return domain.Rebalance{}, fmt.Errorf("failed to convert last update: %w", err)
+		}
+		investments[i].LastUpdate = &converted
+	}
+
+	return calculateRebalance(investments, targets, tolerance, newMoney), nil
+}
+
+func validateTargetAllocations(targets []domain.TargetAllocation, investments []domain.Investment) error {
+	var sum float64
+	var byType, byInvestment bool
+	seen := make(map[string]bool)
+
+	for _, target := range targets {
+		if (target.InvestmentType == nil) == (target.InvestmentID == nil) {
+			return errors.Wrap(domain.ErrInvalidTargetAllocations, "a target needs either an investment type or an investment")
+		}
+		if target.Percentage < 0 || target.Percentage > 100 {
+			return errors.Wrap(domain.ErrInvalidTargetAllocations, "a percentage must be between 0 and 100")
+		}
+
+		key := targetKey(target)
+		if seen[key] {
+			return errors.Wrapf(domain.ErrInvalidTargetAllocations, "duplicate target for %s", key)
+		}
+		seen[key] = true
+
+		if target.InvestmentID != nil {
+			byInvestment = true
+			if !slices.ContainsFunc(investments, func(i domain.Investment) bool { return i.ID == *target.InvestmentID }) {
+				return errors.Wrapf(domain.ErrInvalidTargetAllocations, "unknown investment %s", *target.InvestmentID)
+			}
+		} else {
+			byType = true
+		}
+
+		sum += target.Percentage
+	}
+
+	if byType && byInvestment {
+		return errors.Wrap(domain.ErrInvalidTargetAllocations, "targets cannot mix investment types and investments")
+	}
+	if len(targets) > 0 && math.Abs(sum-100) > 0.01 {
+		return errors.Wrap(domain.ErrInvalidTargetAllocations, "percentages must add up to 100")
+	}
+	return nil
+}
+
+func targetKey(target domain.TargetAllocation) string {
+	if target.InvestmentID != nil {
+		return *target.InvestmentID
+	}
+	return string(*target.InvestmentType)
+}
+
+func calculateRebalance(
+	investments []domain.Investment,
+	targets []domain.TargetAllocation,
+	tolerance float64,
+	newMoney *int64,
+) domain.Rebalance {
+	targetByKey := make(map[string]domain.TargetAllocation)
+	for _, target := range targets {
+		targetByKey[targetKey(target)] = target
+	}
+
+	// investments without a target of their own fall in the group of their type
+	groupKey := func(i domain.Investment) string {
+		if _, ok := targetByKey[i.ID]; ok {
+			return i.ID
+		}
+		if _, ok := targetByKey[string(i.Type)]; ok {
+			return string(i.Type)
+		}
+		return i.ID
+	}
+
+	currentByID := make(map[string]int64)
+	currentByGroup := make(map[string]int64)
+	countByGroup := make(map[string]int)
+	var total int64
+	for _, investment := range investments {
+		var current int64
+		if investment.LastUpdate != nil {
+			current = investment.LastUpdate.Value
+		}
+		currentByID[investment.ID] = current
+		currentByGroup[groupKey(investment)] += current
+		countByGroup[groupKey(investment)]++
+		total += current
+	}
+
+	// a target per type without active investments is left out, so the targets of the groups are scaled back to 100
+	var groupPercentages float64
+	for key := range countByGroup {
+		groupPercentages += targetByKey[key].Percentage
+	}
+	scale := 0.0
+	if groupPercentages > 0 {
+		scale = 100 / groupPercentages
+	}
+
+	// a target per type is split over its investments in proportion to their current value
+	targetPercentageByID := make(map[string]float64)
+	withinToleranceByID := make(map[string]bool)
+	allWithinTolerance := true
+	for _, investment := range investments {
+		key := groupKey(investment)
+		groupPercentage := targetByKey[key].Percentage * scale
+
+		if currentByGroup[key] > 0 {
+			targetPercentageByID[investment.ID] = groupPercentage * float64(currentByID[investment.ID]) / float64(currentByGroup[key])
+		} else {
+			targetPercentageByID[investment.ID] = groupPercentage / float64(countByGroup[key])
+		}
+
+		withinTolerance := total > 0 && math.Abs(percentageOf(currentByGroup[key], total)-groupPercentage) <= tolerance
+		withinToleranceByID[investment.ID] = withinTolerance
+		allWithinTolerance = allWithinTolerance && withinTolerance
+	}
+
+	newTotal := total
+	if newMoney != nil {
+		newTotal += *newMoney
+	}
+
+	amountByID := make(map[string]float64)
+	if newMoney != nil {
+		deficitByID := make(map[string]float64)
+		var deficits float64
+		for _, investment := range investments {
+			target := targetPercentageByID[investment.ID] / 100 * float64(newTotal)
+			deficitByID[investment.ID] = math.Max(0, target-float64(currentByID[investment.ID]))
+			deficits += deficitByID[investment.ID]
+		}
+
+		for _, investment := range investments {
+			if deficits <= float64(*newMoney) {
+				rest := float64(*newMoney) - deficits
+				amountByID[investment.ID] = deficitByID[investment.ID] + rest*targetPercentageByID[investment.ID]/100
+			} else {
+				amountByID[investment.ID] = float64(*newMoney) * deficitByID[investment.ID] / deficits
+			}
+		}
+	} else if !allWithinTolerance {
+		for _, investment := range investments {
+			target := targetPercentageByID[investment.ID] / 100 * float64(newTotal)
+			amountByID[investment.ID] = target - float64(currentByID[investment.ID])
+		}
+	}
+
+	entries := make([]domain.RebalanceEntry, 0)
+	var rounded int64
+	for _, investment := range investments {
+		amount := int64(math.Round(amountByID[investment.ID]))
+		rounded += amount
+		entries = append(entries, domain.NewRebalanceEntry(
+			investment.ID,
+			currentByID[investment.ID],
+			percentageOf(currentByID[investment.ID], total),
+			int64(math.Round(targetPercentageByID[investment.ID]/100*float64(newTotal))),
+			targetPercentageByID[investment.ID],
+			withinToleranceByID[investment.ID],
+			amount,
+		))
+	}
+
+	// the new money is spent to the cent, so rounding differences, at most a cent per entry, go to the largest deposit
+	difference := int64(0)
+	if newMoney != nil {
+		difference = *newMoney - rounded
+	}
+	if difference != 0 && abs(difference) <= int64(len(entries)) {
+		largest := 0
+		for i := range entries {
+			if entries[i].Amount > entries[largest].Amount {
+				largest = i
+			}
+		}
+		entries[largest].Amount += difference
+	}
+
+	return domain.NewRebalance(total, newTotal-total, entries)
+}
+
+func percentageOf(value, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(value) / float64(total) * 100
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
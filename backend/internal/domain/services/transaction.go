@@ -0,0 +1,158 @@
+package services
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/pointer"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type TransactionRepository interface {
+	FindByID(id string) (domain.Transaction, error)
+	Find(query domain.FindTransactionQuery) ([]domain.Transaction, error)
+
+	Create(command domain.SaveTransactionCommand) (domain.Transaction, error)
+	Update(id string, command domain.SaveTransactionCommand) (domain.Transaction, error)
+	DeleteByID(id string) error
+	DeleteByInvestmentID(investmentID string) error
+}
+
+type TransactionService struct {
+	transactionRepository   TransactionRepository
+	investmentUpdateService InvestmentUpdateService
+}
+
+func NewTransactionService(
+	transactionRepository TransactionRepository,
+	investmentUpdateService InvestmentUpdateService,
+) TransactionService {
+	return TransactionService{
+		transactionRepository:   transactionRepository,
+		investmentUpdateService: investmentUpdateService,
+	}
+}
+
+func (s TransactionService) FindByID(id string) (domain.Transaction, error) {
+	return s.transactionRepository.FindByID(id)
+}
+
+func (s TransactionService) Find(query domain.FindTransactionQuery) ([]domain.Transaction, error) {
+	return s.transactionRepository.Find(query)
+}
+
+func (s TransactionService) Create(command domain.SaveTransactionCommand) (domain.Transaction, error) {
+	if command.Investment.Locked {
+		return domain.Transaction{}, domain.ErrInvestmentIsLocked
+	}
+	if command.Investment.IsClosed() {
+		return domain.Transaction{}, domain.ErrInvestmentIsClosed
+	}
+	if err := validateTransaction(command); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return s.transactionRepository.Create(command)
+}
+
+func (s TransactionService) Update(id string, command domain.SaveTransactionCommand) (domain.Transaction, error) {
+	if command.Investment.Locked {
+		return domain.Transaction{}, domain.ErrInvestmentIsLocked
+	}
+	if command.Investment.IsClosed() {
+		return domain.Transaction{}, domain.ErrInvestmentIsClosed
+	}
+	if err := validateTransaction(command); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return s.transactionRepository.Update(id, command)
+}
+
+func (s TransactionService) DeleteByID(id string) error {
+	return s.transactionRepository.DeleteByID(id)
+}
+
+func (s TransactionService) DeleteByInvestmentID(investmentID string) error {
+	return s.transactionRepository.DeleteByInvestmentID(investmentID)
+}
+
+// FindCashFlows sums the ledger entries that moved money into or out of the investment since its previous update, so
+// that an update on the given date can take its deposit, withdrawal and income from the ledger. Buys and incoming
+// transfers count as deposits, sells and outgoing transfers as withdrawals, and dividends and interest as income. Fees
+// and taxes stay inside the investment and show up in its value instead.
+func (s TransactionService) FindCashFlows(investmentID string, date time.Time) (*int64, *int64, *int64, error) {
+	var dateFrom *time.Time
+	lastUpdate, err := s.investmentUpdateService.FindLastByInvestmentIDAndDateLessThanEqual(investmentID, date.AddDate(0, 0, -1))
+	if err != nil {
+		if err != domain.ErrInvestmentUpdateNotFound {
+			return nil, nil, nil, fmt.Errorf("failed to find last update: %w", err)
+		}
+	} else {
+		dateFrom = pointer.Of(lastUpdate.Date.AddDate(0, 0, 1))
+	}
+
+	transactions, err := s.transactionRepository.Find(domain.FindTransactionQuery{
+		InvestmentIDs: []string{investmentID},
+		Types: []domain.TransactionType{
+			domain.TransactionTypeBuy,
+			domain.TransactionTypeSell,
+			domain.TransactionTypeTransfer,
+			domain.TransactionTypeDividend,
+			domain.TransactionTypeInterest,
+		},
+		DateFrom: dateFrom,
+		DateTo:   &date,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to find transactions: %w", err)
+	}
+
+	var deposit, withdrawal, income int64
+	for _, t := range transactions {
+		switch {
+		case t.Type == domain.TransactionTypeBuy:
+			deposit += t.Amount
+		case t.Type == domain.TransactionTypeSell:
+			withdrawal += t.Amount
+		case t.Type == domain.TransactionTypeDividend || t.Type == domain.TransactionTypeInterest:
+			income += t.Amount
+		case t.Amount > 0:
+			deposit += t.Amount
+		default:
+			withdrawal -= t.Amount
+		}
+	}
+
+	return nonZeroOrNil(deposit), nonZeroOrNil(withdrawal), nonZeroOrNil(income), nil
+}
+
+func nonZeroOrNil(amount int64) *int64 {
+	if amount == 0 {
+		return nil
+	}
+	return &amount
+}
+
+func validateTransaction(command domain.SaveTransactionCommand) error {
+	if !command.Type.IsValid() {
+		return errors.Wrapf(domain.ErrInvalidTransaction, "unknown type %q", command.Type)
+	}
+	if command.Type == domain.TransactionTypeTransfer {
+		if command.Amount == 0 {
+			return errors.Wrap(domain.ErrInvalidTransaction, "amount of a transfer cannot be zero")
+		}
+	} else if command.Amount <= 0 {
+		return errors.Wrap(domain.ErrInvalidTransaction, "amount must be positive")
+	}
+	if command.Units != nil {
+		if command.Type != domain.TransactionTypeBuy && command.Type != domain.TransactionTypeSell {
+			return errors.Wrapf(domain.ErrInvalidTransaction, "units are not allowed for type %q", command.Type)
+		}
+		if *command.Units <= 0 {
+			return errors.Wrap(domain.ErrInvalidTransaction, "units must be positive")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package services
+
+// UnitOfWork runs a function in a single transaction. The repositories passed to the function take part in it, so their
+// changes are committed together when the function succeeds and rolled back together when it returns an error.
+type UnitOfWork interface {
+	Do(f func(repositories Repositories) error) error
+}
+
+type Repositories struct {
+	User             UserRepository
+	Settings         SettingsRepository
+	Portfolio        PortfolioRepository
+	Investment       InvestmentRepository
+	InvestmentUpdate InvestmentUpdateRepository
+	Transaction      TransactionRepository
+	TargetAllocation TargetAllocationRepository
+	Tag              TagRepository
+	Goal             GoalRepository
+	ImportProfile    ImportProfileRepository
+	ContributionPlan ContributionPlanRepository
+}
@@ -27,20 +27,23 @@ type UserService struct {
 	userRepository    UserRepository
 	investmentService InvestmentService
 	eventPublisher    EventPublisher
-	settingsService   SettingsService
+	portfolioService  PortfolioService
+	unitOfWork        UnitOfWork
 }
 
 func NewUserService(
 	userRepository UserRepository,
 	investmentService InvestmentService,
 	eventPublisher EventPublisher,
-	settingsService SettingsService,
+	portfolioService PortfolioService,
+	unitOfWork UnitOfWork,
 ) UserService {
 	return UserService{
 		userRepository:    userRepository,
 		investmentService: investmentService,
 		eventPublisher:    eventPublisher,
-		settingsService:   settingsService,
+		portfolioService:  portfolioService,
+		unitOfWork:        unitOfWork,
 	}
 }
 
@@ -48,25 +51,53 @@ func (s UserService) FindDemoUsersCreatedBefore(createdBefore time.Time) ([]doma
 	return s.userRepository.FindDemoUsersCreatedBefore(createdBefore)
 }
 
+// DeleteByID deletes the user together with everything the user owns in a single unit of work.
 func (s UserService) DeleteByID(id string) error {
-	investments, err := s.investmentService.FindByUserID(id)
-	if err != nil {
-		return errors.Wrapf(err, "failed to find investements by user id %s", id)
-	}
+	return s.unitOfWork.Do(func(repositories Repositories) error {
+		investments, err := repositories.Investment.FindByUserID(id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find investements by user id %s", id)
+		}
 
-	for _, investment := range investments {
-		err := s.investmentService.DeleteByID(investment.ID)
+		for _, investment := range investments {
+			err := deleteInvestment(repositories, investment.ID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to delete investment by id %s", investment.ID)
+			}
+		}
+
+		err = repositories.TargetAllocation.DeleteByUserID(id)
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete investment by id %s", investment.ID)
+			return errors.Wrapf(err, "failed to delete target allocations by user id %s", id)
 		}
-	}
 
-	err = s.settingsService.DeleteByUserID(id)
-	if err != nil {
-		return errors.Wrapf(err, "failed to delete settings by user id %s", id)
-	}
+		err = repositories.Goal.DeleteByUserID(id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete goals by user id %s", id)
+		}
+
+		err = repositories.ImportProfile.DeleteByUserID(id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete import profiles by user id %s", id)
+		}
+
+		err = repositories.Tag.DeleteByUserID(id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete tags by user id %s", id)
+		}
+
+		err = repositories.Portfolio.DeleteByUserID(id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete portfolios by user id %s", id)
+		}
+
+		err = repositories.Settings.DeleteByUserID(id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete settings by user id %s", id)
+		}
 
-	return s.userRepository.DeleteByID(id)
+		return repositories.User.DeleteByID(id)
+	})
 }
 
 func (s UserService) FindByEmail(email string) (domain.User, error) {
@@ -81,10 +112,24 @@ func (s UserService) FindByID(id string) (domain.User, error) {
 	return s.userRepository.FindByID(id)
 }
 
+// Create creates the user together with the default portfolio in a single unit of work, so a user is never left
+// without a portfolio.
 func (s UserService) Create(user domain.User) (domain.User, error) {
-	user, err := s.userRepository.Create(user)
+	err := s.unitOfWork.Do(func(repositories Repositories) error {
+		var err error
+		user, err = repositories.User.Create(user)
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		_, err = s.portfolioService.createDefault(repositories, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create default portfolio: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return domain.User{}, fmt.Errorf("failed to create user: %w", err)
+		return domain.User{}, err
 	}
 
 	s.eventPublisher.Publish(domain.NewUserCreatedEvent(user))
@@ -118,6 +163,8 @@ func (s UserService) UpgradeToPremium(user domain.User, stripeCustomerID string)
 	return nil
 }
 
+// DowngradeToBasic locks the active investments beyond the basic plan limit, following the display order chosen by the
+// user.
 func (s UserService) DowngradeToBasic(user domain.User) error {
 	user.AccountType = domain.AccountTypeBasic
 	user.StripeCustomerID = nil
@@ -127,7 +174,7 @@ func (s UserService) DowngradeToBasic(user domain.User) error {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
-	investments, err := s.investmentService.FindByUserID(user.ID)
+	investments, err := s.investmentService.FindActiveByUserID(user.ID)
 	if err != nil {
 		return fmt.Errorf("failed to find investments: %w", err)
 	}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"growfolio/internal/domain"
+	"math"
+	"sort"
+)
+
+const (
+	xirrTolerance     = 1e-7
+	xirrMaxIterations = 100
+	xirrGuess         = 0.1
+	xirrLowerBound    = -0.999999
+	xirrMaxUpperBound = 1e9
+)
+
+// XIRR returns the annualized rate that makes the net present value of the cash flows zero. Newton's method is tried
+// first, because it converges fast for ordinary portfolios. When it fails, the rate is bracketed and bisected.
+func XIRR(cashFlows []domain.CashFlow) (float64, error) {
+	if !hasSignChange(cashFlows) {
+		return 0, domain.ErrCashFlowsWithoutSignChange
+	}
+
+	sorted := make([]domain.CashFlow, len(cashFlows))
+	copy(sorted, cashFlows)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Date.Before(sorted[b].Date) })
+
+	years := make([]float64, len(sorted))
+	amounts := make([]float64, len(sorted))
+	for i, cashFlow := range sorted {
+		years[i] = cashFlow.Date.Sub(sorted[0].Date).Hours() / 24 / 365
+		amounts[i] = float64(cashFlow.Amount)
+	}
+
+	if rate, ok := xirrNewton(years, amounts); ok {
+		return rate, nil
+	}
+	return xirrBisection(years, amounts)
+}
+
+func xirrNewton(years, amounts []float64) (float64, bool) {
+	rate := xirrGuess
+	for i := 0; i < xirrMaxIterations; i++ {
+		value, derivative := npv(rate, years, amounts)
+		if derivative == 0 || math.IsNaN(derivative) || math.IsInf(derivative, 0) {
+			return 0, false
+		}
+
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			return 0, false
+		}
+		if math.Abs(next-rate) < xirrTolerance {
+			return next, true
+		}
+		rate = next
+	}
+	return 0, false
+}
+
+func xirrBisection(years, amounts []float64) (float64, error) {
+	low := xirrLowerBound
+	lowValue, _ := npv(low, years, amounts)
+
+	high := 1.0
+	highValue, _ := npv(high, years, amounts)
+	for sameSign(lowValue, highValue) {
+		high *= 2
+		if high > xirrMaxUpperBound {
+			return 0, domain.ErrXIRRNotConverged
+		}
+		highValue, _ = npv(high, years, amounts)
+	}
+
+	for i := 0; i < 1000; i++ {
+		mid := (low + high) / 2
+		midValue, _ := npv(mid, years, amounts)
+		if math.Abs(high-low) < xirrTolerance || midValue == 0 {
+			return mid, nil
+		}
+
+		if sameSign(lowValue, midValue) {
+			low, lowValue = mid, midValue
+		} else {
+			high = mid
+		}
+	}
+	return 0, domain.ErrXIRRNotConverged
+}
+
+// npv returns the net present value of the cash flows at the given rate and its derivative to the rate.
+func npv(rate float64, years, amounts []float64) (float64, float64) {
+	var value, derivative float64
+	for i := range amounts {
+		discount := math.Pow(1+rate, years[i])
+		value += amounts[i] / discount
+		derivative -= years[i] * amounts[i] / (discount * (1 + rate))
+	}
+	return value, derivative
+}
+
+func hasSignChange(cashFlows []domain.CashFlow) bool {
+	var positive, negative bool
+	for _, cashFlow := range cashFlows {
+		positive = positive || cashFlow.Amount > 0
+		negative = negative || cashFlow.Amount < 0
+	}
+	return positive && negative
+}
+
+func sameSign(a, b float64) bool {
+	return (a < 0) == (b < 0)
+}
@@ -1,20 +1,28 @@
 package domain
 
+// DefaultStaleAfterDays is how many days after its last update an investment is stale, unless the user or the
+// investment sets another threshold.
+const DefaultStaleAfterDays = 30
+
+// Settings hold the preferences of a user. The currency is used for the portfolios that are created without one.
 type Settings struct {
-	UserID   string
-	Currency Currency
+	UserID         string
+	Currency       Currency
+	StaleAfterDays int
 }
 
-func NewSettings(userID string, currency Currency) Settings {
+func NewSettings(userID string, currency Currency, staleAfterDays int) Settings {
 	return Settings{
-		UserID:   userID,
-		Currency: currency,
+		UserID:         userID,
+		Currency:       currency,
+		StaleAfterDays: staleAfterDays,
 	}
 }
 
 func DefaultSettings(userID string) Settings {
 	return Settings{
-		UserID:   userID,
-		Currency: CurrencyUSDollar,
+		UserID:         userID,
+		Currency:       CurrencyUSDollar,
+		StaleAfterDays: DefaultStaleAfterDays,
 	}
 }
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Statement is what the export of a broker tells about an account: the cash that was deposited and withdrawn, and the
+// value of the account on the dates the export has one. Amounts are in cents in the currency of the account.
+type Statement struct {
+	Currency      Currency
+	CashMovements []StatementCashMovement
+	Valuations    []StatementValuation
+}
+
+// StatementCashMovement is a deposit when the amount is positive and a withdrawal when it is negative.
+type StatementCashMovement struct {
+	Date   time.Time
+	Amount int64
+}
+
+func NewStatementCashMovement(date time.Time, amount int64) StatementCashMovement {
+	return StatementCashMovement{
+		Date:   date,
+		Amount: amount,
+	}
+}
+
+type StatementValuation struct {
+	Date  time.Time
+	Value int64
+}
+
+func NewStatementValuation(date time.Time, value int64) StatementValuation {
+	return StatementValuation{
+		Date:  date,
+		Value: value,
+	}
+}
+
+func (s Statement) IsEmpty() bool {
+	return len(s.CashMovements) == 0 && len(s.Valuations) == 0
+}
+
+// ToCreateInvestmentUpdateCommands creates an update for every date with a cash movement or a valuation. On a date
+// without a valuation, the value is the value of the previous update plus the cash that was moved, as if the cash was
+// invested right away. When there is more than one valuation on a date, the last one counts.
+func (s Statement) ToCreateInvestmentUpdateCommands(investment Investment) []CreateInvestmentUpdateCommand {
+	deposits := make(map[time.Time]int64)
+	withdrawals := make(map[time.Time]int64)
+	values := make(map[time.Time]int64)
+	dates := make([]time.Time, 0)
+
+	addDate := func(date time.Time) {
+		_, hasDeposit := deposits[date]
+		_, hasWithdrawal := withdrawals[date]
+		_, hasValue := values[date]
+		if !hasDeposit && !hasWithdrawal && !hasValue {
+			dates = append(dates, date)
+		}
+	}
+
+	for _, movement := range s.CashMovements {
+		addDate(movement.Date)
+		if movement.Amount >= 0 {
+			deposits[movement.Date] += movement.Amount
+		} else {
+			withdrawals[movement.Date] += -movement.Amount
+		}
+	}
+	for _, valuation := range s.Valuations {
+		addDate(valuation.Date)
+		values[valuation.Date] = valuation.Value
+	}
+
+	sort.Slice(dates, func(a, b int) bool { return dates[a].Before(dates[b]) })
+
+	commands := make([]CreateInvestmentUpdateCommand, 0)
+	var previousValue int64
+	for _, date := range dates {
+		var deposit, withdrawal *int64
+		if amount, ok := deposits[date]; ok && amount > 0 {
+			deposit = &amount
+		}
+		if amount, ok := withdrawals[date]; ok && amount > 0 {
+			withdrawal = &amount
+		}
+
+		value, ok := values[date]
+		if !ok {
+			value = max(previousValue+deposits[date]-withdrawals[date], 0)
+		}
+		previousValue = value
+
+		commands = append(commands, NewCreateInvestmentUpdateCommand(
+			investment, date, deposit, withdrawal, nil, nil, value, nil, nil,
+		))
+	}
+
+	return commands
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Tag is a label that a user defines to group investments, next to the fixed investment types. An investment can have
+// many tags and a tag can be on many investments.
+type Tag struct {
+	ID     string
+	UserID string
+	Name   string
+}
+
+func NewTag(id, userID, name string) Tag {
+	return Tag{
+		ID:     id,
+		UserID: userID,
+		Name:   name,
+	}
+}
+
+// TagGroup holds the investments that have the tag. The group without a tag holds the untagged investments.
+type TagGroup struct {
+	Tag         *Tag
+	Investments []Investment
+}
+
+func NewTagGroup(tag *Tag, investments []Investment) TagGroup {
+	return TagGroup{
+		Tag:         tag,
+		Investments: investments,
+	}
+}
+
+type TagAllocation struct {
+	AsOf    time.Time
+	Total   int64
+	Entries []TagAllocationEntry
+}
+
+func NewTagAllocation(asOf time.Time, total int64, entries []TagAllocationEntry) TagAllocation {
+	return TagAllocation{
+		AsOf:    asOf,
+		Total:   total,
+		Entries: entries,
+	}
+}
+
+type TagAllocationEntry struct {
+	Tag        *Tag
+	Value      int64
+	Percentage float64
+}
+
+func NewTagAllocationEntry(tag *Tag, value int64, percentage float64) TagAllocationEntry {
+	return TagAllocationEntry{
+		Tag:        tag,
+		Value:      value,
+		Percentage: percentage,
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+// TargetAllocation is the share of a portfolio that a user wants in either an investment type or a single
+// investment. Exactly one of InvestmentType and InvestmentID is set.
+type TargetAllocation struct {
+	ID             string
+	UserID         string
+	PortfolioID    string
+	InvestmentType *InvestmentType
+	InvestmentID   *string
+	Percentage     float64
+}
+
+func NewTargetAllocation(
+	id,
+	userID,
+	portfolioID string,
+	investmentType *InvestmentType,
+	investmentID *string,
+	percentage float64,
+) TargetAllocation {
+	return TargetAllocation{
+		ID:             id,
+		UserID:         userID,
+		PortfolioID:    portfolioID,
+		InvestmentType: investmentType,
+		InvestmentID:   investmentID,
+		Percentage:     percentage,
+	}
+}
+
+type Rebalance struct {
+	Total    int64
+	NewMoney int64
+	Entries  []RebalanceEntry
+}
+
+func NewRebalance(total, newMoney int64, entries []RebalanceEntry) Rebalance {
+	return Rebalance{
+		Total:    total,
+		NewMoney: newMoney,
+		Entries:  entries,
+	}
+}
+
+// RebalanceEntry suggests a deposit (positive Amount) or withdrawal (negative Amount) for an investment.
+type RebalanceEntry struct {
+	InvestmentID      string
+	CurrentValue      int64
+	CurrentPercentage float64
+	TargetValue       int64
+	TargetPercentage  float64
+	WithinTolerance   bool
+	Amount            int64
+}
+
+func NewRebalanceEntry(
+	investmentID string,
+	currentValue int64,
+	currentPercentage float64,
+	targetValue int64,
+	targetPercentage float64,
+	withinTolerance bool,
+	amount int64,
+) RebalanceEntry {
+	return RebalanceEntry{
+		InvestmentID:      investmentID,
+		CurrentValue:      currentValue,
+		CurrentPercentage: currentPercentage,
+		TargetValue:       targetValue,
+		TargetPercentage:  targetPercentage,
+		WithinTolerance:   withinTolerance,
+		Amount:            amount,
+	}
+}
@@ -0,0 +1,90 @@
+package domain
+
+import "time"
+
+type TransactionType string
+
+const (
+	TransactionTypeBuy      TransactionType = "buy"
+	TransactionTypeSell     TransactionType = "sell"
+	TransactionTypeDividend TransactionType = "dividend"
+	TransactionTypeInterest TransactionType = "interest"
+	TransactionTypeFee      TransactionType = "fee"
+	TransactionTypeTax      TransactionType = "tax"
+	TransactionTypeTransfer TransactionType = "transfer"
+)
+
+func (t TransactionType) IsValid() bool {
+	switch t {
+	case TransactionTypeBuy, TransactionTypeSell, TransactionTypeDividend, TransactionTypeInterest,
+		TransactionTypeFee, TransactionTypeTax, TransactionTypeTransfer:
+		return true
+	}
+	return false
+}
+
+// Transaction is a single entry in the ledger of an investment. Amounts are positive, except for transfers, where a
+// positive amount moves money into the investment and a negative amount moves it out.
+type Transaction struct {
+	ID           string
+	InvestmentID string
+	Date         time.Time
+	Type         TransactionType
+	Amount       int64
+	Units        *float64
+	Note         *string
+}
+
+func NewTransaction(
+	id,
+	investmentID string,
+	date time.Time,
+	t TransactionType,
+	amount int64,
+	units *float64,
+	note *string,
+) Transaction {
+	return Transaction{
+		ID:           id,
+		InvestmentID: investmentID,
+		Date:         date,
+		Type:         t,
+		Amount:       amount,
+		Units:        units,
+		Note:         note,
+	}
+}
+
+type SaveTransactionCommand struct {
+	Investment Investment
+	Date       time.Time
+	Type       TransactionType
+	Amount     int64
+	Units      *float64
+	Note       *string
+}
+
+func NewSaveTransactionCommand(
+	investment Investment,
+	date time.Time,
+	t TransactionType,
+	amount int64,
+	units *float64,
+	note *string,
+) SaveTransactionCommand {
+	return SaveTransactionCommand{
+		Investment: investment,
+		Date:       date,
+		Type:       t,
+		Amount:     amount,
+		Units:      units,
+		Note:       note,
+	}
+}
+
+type FindTransactionQuery struct {
+	InvestmentIDs []string
+	Types         []TransactionType
+	DateFrom      *time.Time
+	DateTo        *time.Time
+}
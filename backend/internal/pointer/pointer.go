@@ -23,6 +23,13 @@ func IntToString(i *int64) string {
 	return strconv.FormatInt(*i, 10)
 }
 
+func FloatToString(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
 func IntOrNil(i int64) *int64 {
 	if i == 0 {
 		return nil
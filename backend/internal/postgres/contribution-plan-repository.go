@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"growfolio/internal/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type ContributionPlan struct {
+	ID           uuid.UUID `db:"id"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	InvestmentID string    `db:"investment_id"`
+	Amount       int64     `db:"amount"`
+	Frequency    string    `db:"frequency"`
+	DayOfMonth   int       `db:"day_of_month"`
+	NextRunDate  time.Time `db:"next_run_date"`
+	Paused       bool      `db:"paused"`
+}
+
+type ContributionPlanRepository struct {
+	db querier
+}
+
+func NewContributionPlanRepository(db *sqlx.DB) ContributionPlanRepository {
+	return ContributionPlanRepository{db: db}
+}
+
+func (r ContributionPlanRepository) FindByID(id string) (domain.ContributionPlan, error) {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return domain.ContributionPlan{}, domain.ErrContributionPlanNotFound
+	}
+
+	entity := ContributionPlan{}
+	err = r.db.Get(&entity, "SELECT * FROM contribution_plan WHERE id=$1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ContributionPlan{}, domain.ErrContributionPlanNotFound
+		}
+		return domain.ContributionPlan{}, fmt.Errorf("failed to select contribution plan: %w", err)
+	}
+
+	return r.toDomainContributionPlan(entity)
+}
+
+func (r ContributionPlanRepository) FindByInvestmentIDs(investmentIDs []string) ([]domain.ContributionPlan, error) {
+	query, args, err := sq.Select("*").
+		From("contribution_plan").
+		Where(sq.Eq{"investment_id": investmentIDs}).
+		OrderBy("next_run_date ASC", "created_at ASC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	entities := []ContributionPlan{}
+	err = r.db.Select(&entities, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select contribution plans: %w", err)
+	}
+
+	return r.toDomainContributionPlans(entities)
+}
+
+// FindDue finds the plans that are not paused and should have run on or before the given date.
+func (r ContributionPlanRepository) FindDue(date time.Time) ([]domain.ContributionPlan, error) {
+	entities := []ContributionPlan{}
+	err := r.db.Select(&entities, `
+		SELECT *
+		FROM contribution_plan
+		WHERE NOT paused
+		AND next_run_date <= $1
+		ORDER BY next_run_date ASC, created_at ASC
+	`, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to select contribution plans: %w", err)
+	}
+
+	return r.toDomainContributionPlans(entities)
+}
+
+func (r ContributionPlanRepository) Create(plan domain.ContributionPlan) (domain.ContributionPlan, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to generate new UUID: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO contribution_plan (id, investment_id, amount, frequency, day_of_month, next_run_date, paused)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, id, plan.InvestmentID, plan.Amount, plan.Frequency, plan.DayOfMonth, plan.NextRunDate.Format("2006-01-02"), plan.Paused)
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to insert contribution plan: %w", err)
+	}
+
+	return r.FindByID(id.String())
+}
+
+// Update overwrites the plan together with its skipped months.
+func (r ContributionPlanRepository) Update(plan domain.ContributionPlan) (domain.ContributionPlan, error) {
+	tx, err := begin(r.db)
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE contribution_plan
+		SET amount = $2, frequency = $3, day_of_month = $4, next_run_date = $5, paused = $6
+		WHERE id = $1
+	`, plan.ID, plan.Amount, plan.Frequency, plan.DayOfMonth, plan.NextRunDate.Format("2006-01-02"), plan.Paused)
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to update contribution plan: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM contribution_plan_skipped_month WHERE contribution_plan_id=$1", plan.ID)
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to delete skipped months: %w", err)
+	}
+	for _, month := range plan.SkippedMonths {
+		_, err = tx.Exec(`
+			INSERT INTO contribution_plan_skipped_month (contribution_plan_id, "month")
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, plan.ID, month.Format("2006-01-02"))
+		if err != nil {
+			return domain.ContributionPlan{}, fmt.Errorf("failed to insert skipped month: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.FindByID(plan.ID)
+}
+
+func (r ContributionPlanRepository) DeleteByID(id string) error {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.db.Exec("DELETE FROM contribution_plan WHERE id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete contribution plan: %w", err)
+	}
+
+	return nil
+}
+
+func (r ContributionPlanRepository) toDomainContributionPlans(entities []ContributionPlan) ([]domain.ContributionPlan, error) {
+	plans := make([]domain.ContributionPlan, 0)
+	for _, entity := range entities {
+		plan, err := r.toDomainContributionPlan(entity)
+		if err != nil {
+			return []domain.ContributionPlan{}, fmt.Errorf("failed to map entity to contribution plan: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+func (r ContributionPlanRepository) toDomainContributionPlan(p ContributionPlan) (domain.ContributionPlan, error) {
+	skippedMonths := []time.Time{}
+	err := r.db.Select(&skippedMonths, `
+		SELECT "month" FROM contribution_plan_skipped_month WHERE contribution_plan_id=$1 ORDER BY "month"
+	`, p.ID)
+	if err != nil {
+		return domain.ContributionPlan{}, fmt.Errorf("failed to select skipped months: %w", err)
+	}
+
+	return domain.NewContributionPlan(
+		p.ID.String(),
+		p.InvestmentID,
+		p.Amount,
+		domain.ContributionFrequency(p.Frequency),
+		p.DayOfMonth,
+		p.NextRunDate,
+		p.Paused,
+		skippedMonths,
+	), nil
+}
@@ -0,0 +1,14 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx"
+	"github.com/pkg/errors"
+)
+
+// uniqueViolationCode is the SQLSTATE that Postgres returns when an insert or update breaks a unique constraint.
+const uniqueViolationCode = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pgErr pgx.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
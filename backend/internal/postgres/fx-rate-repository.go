@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type FXRate struct {
+	Date      time.Time `db:"date"`
+	Currency  string    `db:"currency"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Rate      float64   `db:"rate"`
+}
+
+func (r FXRate) toDomainFXRate() domain.FXRate {
+	return domain.NewFXRate(r.Date, domain.Currency(r.Currency), r.Rate)
+}
+
+type FXRateRepository struct {
+	db querier
+}
+
+func NewFXRateRepository(db *sqlx.DB) FXRateRepository {
+	return FXRateRepository{db: db}
+}
+
+func (r FXRateRepository) FindByCurrencies(currencies []domain.Currency) ([]domain.FXRate, error) {
+	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("*").
+		From("fx_rate").
+		Where(sq.Eq{"currency": slices.Map(currencies, func(c domain.Currency) string { return string(c) })}).
+		OrderBy("date ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	entities := []FXRate{}
+	err = r.db.Select(&entities, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select fx rates: %w", err)
+	}
+
+	return slices.Map(entities, func(e FXRate) domain.FXRate { return e.toDomainFXRate() }), nil
+}
+
+func (r FXRateRepository) Save(rates []domain.FXRate) error {
+	tx, err := begin(r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		_, err := tx.Exec(`
+			INSERT INTO fx_rate ("date", currency, rate)
+			VALUES ($1, $2, $3)
+			ON CONFLICT ("date", currency) DO UPDATE SET rate = EXCLUDED.rate
+		`, rate.Date, rate.Currency, rate.Rate)
+		if err != nil {
+			return fmt.Errorf("failed to upsert fx rate: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
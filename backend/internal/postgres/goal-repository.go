@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"growfolio/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type Goal struct {
+	ID                       uuid.UUID `db:"id"`
+	CreatedAt                time.Time `db:"created_at"`
+	UpdatedAt                time.Time `db:"updated_at"`
+	UserID                   string    `db:"user_id"`
+	Name                     string    `db:"name"`
+	TargetAmount             int64     `db:"target_amount"`
+	TargetDate               time.Time `db:"target_date"`
+	Currency                 string    `db:"currency"`
+	ExpectedReturnPercentage float64   `db:"expected_return_percentage"`
+	Status                   *string   `db:"status"`
+}
+
+type GoalRepository struct {
+	db querier
+}
+
+func NewGoalRepository(db *sqlx.DB) GoalRepository {
+	return GoalRepository{db: db}
+}
+
+func (r GoalRepository) FindByID(id string) (domain.Goal, error) {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return domain.Goal{}, domain.ErrGoalNotFound
+	}
+
+	entity := Goal{}
+	err = r.db.Get(&entity, "SELECT * FROM goal WHERE id=$1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Goal{}, domain.ErrGoalNotFound
+		}
+		return domain.Goal{}, fmt.Errorf("failed to select goal: %w", err)
+	}
+
+	return r.toDomainGoal(entity)
+}
+
+func (r GoalRepository) FindByUserID(userID string) ([]domain.Goal, error) {
+	entities := []Goal{}
+	err := r.db.Select(&entities, "SELECT * FROM goal WHERE user_id=$1 ORDER BY target_date ASC, created_at ASC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select goals: %w", err)
+	}
+
+	return r.toDomainGoals(entities)
+}
+
+func (r GoalRepository) FindAll() ([]domain.Goal, error) {
+	entities := []Goal{}
+	err := r.db.Select(&entities, "SELECT * FROM goal ORDER BY created_at ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to select goals: %w", err)
+	}
+
+	return r.toDomainGoals(entities)
+}
+
+func (r GoalRepository) Create(userID string, command domain.SaveGoalCommand) (domain.Goal, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to generate new UUID: %w", err)
+	}
+
+	tx, err := begin(r.db)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO goal (id, user_id, "name", target_amount, target_date, currency, expected_return_percentage)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, id, userID, command.Name, command.TargetAmount, command.TargetDate, command.Currency, command.ExpectedReturnPercentage)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to insert goal: %w", err)
+	}
+
+	err = r.replaceMembers(tx, id.String(), command)
+	if err != nil {
+		return domain.Goal{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.FindByID(id.String())
+}
+
+func (r GoalRepository) Update(id string, command domain.SaveGoalCommand) (domain.Goal, error) {
+	tx, err := begin(r.db)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE goal
+		SET "name" = $2, target_amount = $3, target_date = $4, currency = $5, expected_return_percentage = $6
+		WHERE id = $1
+	`, id, command.Name, command.TargetAmount, command.TargetDate, command.Currency, command.ExpectedReturnPercentage)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to update goal: %w", err)
+	}
+
+	err = r.replaceMembers(tx, id, command)
+	if err != nil {
+		return domain.Goal{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.FindByID(id)
+}
+
+func (r GoalRepository) UpdateStatus(id string, status domain.GoalStatus) error {
+	_, err := r.db.Exec("UPDATE goal SET status = $2 WHERE id = $1", id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update goal status: %w", err)
+	}
+
+	return nil
+}
+
+func (r GoalRepository) DeleteByID(id string) error {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.db.Exec("DELETE FROM goal WHERE id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+
+	return nil
+}
+
+func (r GoalRepository) DeleteByUserID(userID string) error {
+	_, err := r.db.Exec("DELETE FROM goal WHERE user_id=$1", userID)
+	return err
+}
+
+// replaceMembers overwrites the investments and tags that count toward the goal.
+func (r GoalRepository) replaceMembers(tx querier, id string, command domain.SaveGoalCommand) error {
+	_, err := tx.Exec("DELETE FROM goal_investment WHERE goal_id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal investments: %w", err)
+	}
+	for _, investmentID := range command.InvestmentIDs {
+		_, err = tx.Exec("INSERT INTO goal_investment (goal_id, investment_id) VALUES ($1, $2)", id, investmentID)
+		if err != nil {
+			return fmt.Errorf("failed to insert goal investment: %w", err)
+		}
+	}
+
+	_, err = tx.Exec("DELETE FROM goal_tag WHERE goal_id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal tags: %w", err)
+	}
+	for _, tagID := range command.TagIDs {
+		_, err = tx.Exec("INSERT INTO goal_tag (goal_id, tag_id) VALUES ($1, $2)", id, tagID)
+		if err != nil {
+			return fmt.Errorf("failed to insert goal tag: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r GoalRepository) toDomainGoals(entities []Goal) ([]domain.Goal, error) {
+	goals := make([]domain.Goal, 0)
+	for _, entity := range entities {
+		goal, err := r.toDomainGoal(entity)
+		if err != nil {
+			return []domain.Goal{}, fmt.Errorf("failed to map entity to goal: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+
+	return goals, nil
+}
+
+func (r GoalRepository) toDomainGoal(g Goal) (domain.Goal, error) {
+	investmentIDs := []string{}
+	err := r.db.Select(&investmentIDs, "SELECT investment_id FROM goal_investment WHERE goal_id=$1 ORDER BY investment_id", g.ID)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to select investment ids: %w", err)
+	}
+
+	tagIDs := []string{}
+	err = r.db.Select(&tagIDs, "SELECT tag_id FROM goal_tag WHERE goal_id=$1 ORDER BY tag_id", g.ID)
+	if err != nil {
+		return domain.Goal{}, fmt.Errorf("failed to select tag ids: %w", err)
+	}
+
+	var status *domain.GoalStatus
+	if g.Status != nil {
+		converted := domain.GoalStatus(*g.Status)
+		status = &converted
+	}
+
+	return domain.NewGoal(
+		g.ID.String(),
+		g.UserID,
+		g.Name,
+		g.TargetAmount,
+		g.TargetDate,
+		domain.Currency(g.Currency),
+		g.ExpectedReturnPercentage,
+		investmentIDs,
+		tagIDs,
+		status,
+	), nil
+}
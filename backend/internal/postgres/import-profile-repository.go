@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"growfolio/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type ImportProfile struct {
+	ID                 uuid.UUID `db:"id"`
+	CreatedAt          time.Time `db:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+	UserID             string    `db:"user_id"`
+	Name               string    `db:"name"`
+	Delimiter          string    `db:"delimiter"`
+	DateFormat         string    `db:"date_format"`
+	DecimalSeparator   string    `db:"decimal_separator"`
+	ThousandsSeparator string    `db:"thousands_separator"`
+	AmountScale        string    `db:"amount_scale"`
+}
+
+type ImportProfileColumn struct {
+	Field  string `db:"field"`
+	Header string `db:"header"`
+}
+
+type ImportProfileRepository struct {
+	db querier
+}
+
+func NewImportProfileRepository(db *sqlx.DB) ImportProfileRepository {
+	return ImportProfileRepository{db: db}
+}
+
+func (r ImportProfileRepository) FindByID(id string) (domain.ImportProfile, error) {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return domain.ImportProfile{}, domain.ErrImportProfileNotFound
+	}
+
+	entity := ImportProfile{}
+	err = r.db.Get(&entity, "SELECT * FROM import_profile WHERE id=$1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ImportProfile{}, domain.ErrImportProfileNotFound
+		}
+		return domain.ImportProfile{}, fmt.Errorf("failed to select import profile: %w", err)
+	}
+
+	return r.toDomainImportProfile(entity)
+}
+
+func (r ImportProfileRepository) FindByUserID(userID string) ([]domain.ImportProfile, error) {
+	entities := []ImportProfile{}
+	err := r.db.Select(&entities, `SELECT * FROM import_profile WHERE user_id=$1 ORDER BY "name" ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select import profiles: %w", err)
+	}
+
+	profiles := make([]domain.ImportProfile, 0)
+	for _, entity := range entities {
+		profile, err := r.toDomainImportProfile(entity)
+		if err != nil {
+			return []domain.ImportProfile{}, fmt.Errorf("failed to map entity to import profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func (r ImportProfileRepository) Create(userID string, command domain.SaveImportProfileCommand) (domain.ImportProfile, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to generate new UUID: %w", err)
+	}
+
+	tx, err := begin(r.db)
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO import_profile (
+			id, user_id, "name", delimiter, date_format, decimal_separator, thousands_separator, amount_scale
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, id, userID, command.Name, command.Delimiter, command.DateFormat, command.DecimalSeparator,
+		command.ThousandsSeparator, command.AmountScale)
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to insert import profile: %w", err)
+	}
+
+	err = r.replaceColumns(tx, id.String(), command.ColumnMapping)
+	if err != nil {
+		return domain.ImportProfile{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.FindByID(id.String())
+}
+
+func (r ImportProfileRepository) Update(id string, command domain.SaveImportProfileCommand) (domain.ImportProfile, error) {
+	tx, err := begin(r.db)
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE import_profile
+		SET "name" = $2, delimiter = $3, date_format = $4, decimal_separator = $5, thousands_separator = $6,
+			amount_scale = $7
+		WHERE id = $1
+	`, id, command.Name, command.Delimiter, command.DateFormat, command.DecimalSeparator, command.ThousandsSeparator,
+		command.AmountScale)
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to update import profile: %w", err)
+	}
+
+	err = r.replaceColumns(tx, id, command.ColumnMapping)
+	if err != nil {
+		return domain.ImportProfile{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.FindByID(id)
+}
+
+func (r ImportProfileRepository) DeleteByID(id string) error {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.db.Exec("DELETE FROM import_profile WHERE id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete import profile: %w", err)
+	}
+
+	return nil
+}
+
+func (r ImportProfileRepository) DeleteByUserID(userID string) error {
+	_, err := r.db.Exec("DELETE FROM import_profile WHERE user_id=$1", userID)
+	return err
+}
+
+// replaceColumns overwrites the column mapping of the import profile.
+func (r ImportProfileRepository) replaceColumns(tx querier, id string, columnMapping map[string]string) error {
+	_, err := tx.Exec("DELETE FROM import_profile_column WHERE import_profile_id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete import profile columns: %w", err)
+	}
+
+	for field, header := range columnMapping {
+		_, err = tx.Exec(
+			"INSERT INTO import_profile_column (import_profile_id, field, header) VALUES ($1, $2, $3)",
+			id, field, header,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert import profile column: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r ImportProfileRepository) toDomainImportProfile(p ImportProfile) (domain.ImportProfile, error) {
+	columns := []ImportProfileColumn{}
+	err := r.db.Select(&columns, "SELECT field, header FROM import_profile_column WHERE import_profile_id=$1", p.ID)
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("failed to select import profile columns: %w", err)
+	}
+
+	columnMapping := make(map[string]string)
+	for _, column := range columns {
+		columnMapping[column.Field] = column.Header
+	}
+
+	return domain.NewImportProfile(
+		p.ID.String(),
+		p.UserID,
+		p.Name,
+		p.Delimiter,
+		p.DateFormat,
+		p.DecimalSeparator,
+		p.ThousandsSeparator,
+		columnMapping,
+		domain.AmountScale(p.AmountScale),
+	), nil
+}
@@ -13,17 +13,24 @@ import (
 )
 
 type Investment struct {
-	ID        uuid.UUID             `db:"id"`
-	CreatedAt time.Time             `db:"created_at"`
-	UpdatedAt time.Time             `db:"updated_at"`
-	Type      domain.InvestmentType `db:"type"`
-	Name      string                `db:"name"`
-	UserID    string                `db:"user_id"`
-	Locked    bool                  `db:"locked"`
+	ID                  uuid.UUID             `db:"id"`
+	CreatedAt           time.Time             `db:"created_at"`
+	UpdatedAt           time.Time             `db:"updated_at"`
+	Type                domain.InvestmentType `db:"type"`
+	Name                string                `db:"name"`
+	Currency            string                `db:"currency"`
+	AnnualFeePercentage *float64              `db:"annual_fee_percentage"`
+	UserID              string                `db:"user_id"`
+	PortfolioID         uuid.UUID             `db:"portfolio_id"`
+	Locked              bool                  `db:"locked"`
+	DisplayOrder        int                   `db:"display_order"`
+	ClosedAt            *time.Time            `db:"closed_at"`
+	StaleAfterDays      *int                  `db:"stale_after_days"`
+	StaleRemindedAt     *time.Time            `db:"stale_reminded_at"`
 }
 
 type InvestmentRepository struct {
-	db                         *sqlx.DB
+	db                         querier
 	investmentUpdateRepository InvestmentUpdateRepository
 }
 
@@ -39,21 +46,32 @@ func NewInvestmentRepository(
 
 func (r InvestmentRepository) FindByUserID(userID string) ([]domain.Investment, error) {
 	entities := []Investment{}
-	err := r.db.Select(&entities, "SELECT * FROM investment WHERE user_id=$1 ORDER BY created_at ASC", userID)
+	err := r.db.Select(&entities, "SELECT * FROM investment WHERE user_id=$1 ORDER BY display_order ASC, created_at ASC", userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to select investments: %w", err)
 	}
 
-	investments := make([]domain.Investment, 0)
-	for _, entity := range entities {
-		investment, err := r.toDomainInvestment(entity)
-		if err != nil {
-			return []domain.Investment{}, fmt.Errorf("failed to map entity to investment: %w", err)
-		}
-		investments = append(investments, investment)
+	return r.toDomainInvestments(entities)
+}
+
+func (r InvestmentRepository) FindAll() ([]domain.Investment, error) {
+	entities := []Investment{}
+	err := r.db.Select(&entities, "SELECT * FROM investment ORDER BY user_id ASC, display_order ASC, created_at ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to select investments: %w", err)
 	}
 
-	return investments, nil
+	return r.toDomainInvestments(entities)
+}
+
+func (r InvestmentRepository) FindByPortfolioID(portfolioID string) ([]domain.Investment, error) {
+	entities := []Investment{}
+	err := r.db.Select(&entities, "SELECT * FROM investment WHERE portfolio_id=$1 ORDER BY display_order ASC, created_at ASC", portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select investments: %w", err)
+	}
+
+	return r.toDomainInvestments(entities)
 }
 
 func (r InvestmentRepository) DeleteByID(id string) error {
@@ -93,10 +111,10 @@ func (r InvestmentRepository) Create(c domain.CreateInvestmentCommand) (domain.I
 
 	var entity Investment
 	err = r.db.QueryRowx(`
-		INSERT INTO investment (id, "type", "name", user_id, locked) 
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO investment (id, "type", "name", currency, annual_fee_percentage, user_id, portfolio_id, locked, display_order) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT COALESCE(MAX(display_order) + 1, 0) FROM investment WHERE portfolio_id = $7))
 		RETURNING *
-	`, id, c.Type, c.Name, c.User.ID, c.Locked).StructScan(&entity)
+	`, id, c.Type, c.Name, c.Currency, c.AnnualFeePercentage, c.User.ID, c.Portfolio.ID, c.Locked).StructScan(&entity)
 	if err != nil {
 		return domain.Investment{}, fmt.Errorf("failed to insert investment: %w", err)
 	}
@@ -115,13 +133,109 @@ func (r InvestmentRepository) UpdateLocked(id string, locked bool) error {
 	return err
 }
 
+func (r InvestmentRepository) Update(
+	id string,
+	t domain.InvestmentType,
+	name string,
+	staleAfterDays *int,
+) (domain.Investment, error) {
+	var entity Investment
+	err := r.db.QueryRowx(`
+		UPDATE investment
+		SET "type" = $2, "name" = $3, stale_after_days = $4
+		WHERE id = $1
+		RETURNING *;
+	`, id, t, name, staleAfterDays).StructScan(&entity)
+	if err != nil {
+		return domain.Investment{}, fmt.Errorf("failed to update investment: %w", err)
+	}
+
+	return r.toDomainInvestment(entity)
+}
+
+func (r InvestmentRepository) UpdateClosedAt(id string, closedAt *time.Time) error {
+	_, err := r.db.Exec("UPDATE investment SET closed_at = $2 WHERE id = $1", id, closedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update closed at: %w", err)
+	}
+
+	return nil
+}
+
+func (r InvestmentRepository) UpdateStaleRemindedAt(id string, staleRemindedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE investment SET stale_reminded_at = $2 WHERE id = $1", id, staleRemindedAt.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("failed to update stale reminded at: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDisplayOrders sets the display order of every investment in a single transaction, so the order is never
+// left half updated.
+func (r InvestmentRepository) UpdateDisplayOrders(displayOrderByID map[string]int) error {
+	tx, err := begin(r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for id, displayOrder := range displayOrderByID {
+		_, err := tx.Exec("UPDATE investment SET display_order = $2 WHERE id = $1", id, displayOrder)
+		if err != nil {
+			return fmt.Errorf("failed to update display order: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r InvestmentRepository) toDomainInvestments(entities []Investment) ([]domain.Investment, error) {
+	investments := make([]domain.Investment, 0)
+	for _, entity := range entities {
+		investment, err := r.toDomainInvestment(entity)
+		if err != nil {
+			return []domain.Investment{}, fmt.Errorf("failed to map entity to investment: %w", err)
+		}
+		investments = append(investments, investment)
+	}
+
+	return investments, nil
+}
+
 func (r InvestmentRepository) toDomainInvestment(i Investment) (domain.Investment, error) {
 	lastUpdate, err := r.findLastUpdate(i)
 	if err != nil {
 		return domain.Investment{}, fmt.Errorf("failed to find last update: %w", err)
 	}
 
-	return domain.NewInvestment(i.ID.String(), i.Type, i.Name, i.UserID, i.Locked, lastUpdate), nil
+	tagIDs := []string{}
+	err = r.db.Select(&tagIDs, "SELECT tag_id FROM investment_tag WHERE investment_id=$1 ORDER BY tag_id", i.ID)
+	if err != nil {
+		return domain.Investment{}, fmt.Errorf("failed to select tag ids: %w", err)
+	}
+
+	return domain.NewInvestment(
+		i.ID.String(),
+		i.Type,
+		i.Name,
+		domain.Currency(i.Currency),
+		i.AnnualFeePercentage,
+		i.UserID,
+		i.PortfolioID.String(),
+		i.Locked,
+		i.DisplayOrder,
+		i.ClosedAt,
+		i.StaleAfterDays,
+		i.StaleRemindedAt,
+		tagIDs,
+		lastUpdate,
+	), nil
 }
 
 func (r InvestmentRepository) findLastUpdate(i Investment) (*domain.InvestmentUpdate, error) {
@@ -21,23 +21,32 @@ type InvestmentUpdate struct {
 	Date         time.Time `db:"date"`
 	Deposit      *int64    `db:"deposit"`
 	Withdrawal   *int64    `db:"withdrawal"`
+	Income       *int64    `db:"income"`
+	Fee          *int64    `db:"fee"`
 	Value        int64     `db:"value"`
+	Units        *float64  `db:"units"`
+	Price        *float64  `db:"price"`
 }
 
-func (u InvestmentUpdate) toDomainInvestmentUpdate(cost int64) domain.InvestmentUpdate {
+func (u InvestmentUpdate) toDomainInvestmentUpdate(costs CalculateCostRow) domain.InvestmentUpdate {
 	return domain.NewInvestmentUpdate(
 		u.ID.String(),
 		u.InvestmentID,
 		u.Date,
 		u.Deposit,
 		u.Withdrawal,
-		cost,
+		u.Income,
+		u.Fee,
+		costs.Cost,
+		costs.Fees,
 		u.Value,
+		u.Units,
+		u.Price,
 	)
 }
 
 type InvestmentUpdateRepository struct {
-	db *sqlx.DB
+	db querier
 }
 
 func NewInvestmentUpdateRepository(db *sqlx.DB) InvestmentUpdateRepository {
@@ -108,6 +117,9 @@ func (r InvestmentUpdateRepository) Find(findQuery domain.FindInvestmentUpdateQu
 	if findQuery.DateFrom != nil {
 		queryBuilder = queryBuilder.Where(sq.Expr("date >= ?", *findQuery.DateFrom))
 	}
+	if findQuery.DateTo != nil {
+		queryBuilder = queryBuilder.Where(sq.Expr("date <= ?", *findQuery.DateTo))
+	}
 
 	queryBuilder = queryBuilder.OrderBy("date ASC")
 	query, args, err := queryBuilder.ToSql()
@@ -164,16 +176,53 @@ func (r InvestmentUpdateRepository) Create(c domain.CreateInvestmentUpdateComman
 	}
 
 	_, err = r.db.Exec(`
-		INSERT INTO investment_update (id, investment_id, "date", deposit, withdrawal, "value") 
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, id, c.Investment.ID, c.Date, c.Deposit, c.Withdrawal, c.Value)
+		INSERT INTO investment_update (id, investment_id, "date", deposit, withdrawal, income, fee, "value", units, price) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, id, c.Investment.ID, c.Date, c.Deposit, c.Withdrawal, c.Income, c.Fee, c.Value, c.Units, c.Price)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.InvestmentUpdate{}, domain.ErrInvestmentUpdateExists
+		}
 		return domain.InvestmentUpdate{}, fmt.Errorf("failed to insert investment update: %w", err)
 	}
 
 	return r.FindByID(id.String())
 }
 
+func (r InvestmentUpdateRepository) Update(id string, c domain.CreateInvestmentUpdateCommand) error {
+	_, err := r.db.Exec(`
+		UPDATE investment_update
+		SET "date" = $2, deposit = $3, withdrawal = $4, income = $5, fee = $6, "value" = $7, units = $8, price = $9
+		WHERE id = $1
+	`, id, c.Date, c.Deposit, c.Withdrawal, c.Income, c.Fee, c.Value, c.Units, c.Price)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrInvestmentUpdateExists
+		}
+		return fmt.Errorf("failed to update investment update: %w", err)
+	}
+
+	return nil
+}
+
+func (r InvestmentUpdateRepository) FindByInvestmentIDAndDate(investmentID string, date time.Time) (domain.InvestmentUpdate, error) {
+	entity := InvestmentUpdate{}
+	err := r.db.Get(&entity, `
+		SELECT *
+		FROM investment_update
+		WHERE investment_id = $1
+		AND date = $2
+	`, investmentID, date.Format("2006-01-02"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.InvestmentUpdate{}, domain.ErrInvestmentUpdateNotFound
+		}
+		return domain.InvestmentUpdate{}, fmt.Errorf("failed to select investment update: %w", err)
+	}
+
+	return r.toDomainInvestmentUpdate(entity)
+}
+
 func (r InvestmentUpdateRepository) FindLastByInvestmentIDAndDateLessThanEqual(
 	investmentID string,
 	date time.Time,
@@ -226,15 +275,50 @@ func (r InvestmentUpdateRepository) toDomainInvestmentUpdate(entity InvestmentUp
 type CalculateCostRow struct {
 	ID   uuid.UUID `db:"id"`
 	Cost int64     `db:"cost"`
+	Fees int64     `db:"fees"`
 }
 
-func (r InvestmentUpdateRepository) calculateCosts(ids []uuid.UUID) (map[uuid.UUID]int64, error) {
+// calculateCosts sums the costs and the fees up to and including every update. An update without a fee of its own
+// takes the fees in the ledger since the previous update, like the fee report does.
+func (r InvestmentUpdateRepository) calculateCosts(ids []uuid.UUID) (map[uuid.UUID]CalculateCostRow, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	withPreviousDate := psql.
+		Select(
+			"id",
+			"investment_id",
+			`"date"`,
+			"deposit",
+			"withdrawal",
+			"fee",
+			`LAG("date") OVER (PARTITION BY investment_id ORDER BY "date") AS previous_date`,
+		).
+		From("investment_update")
+	withLedgerFees := psql.
+		Select(
+			"id",
+			"investment_id",
+			`"date"`,
+			"deposit",
+			"withdrawal",
+			`COALESCE(u.fee, (
+				SELECT SUM(t.amount)
+				FROM transaction t
+				WHERE t.investment_id = u.investment_id
+					AND t."type" = 'fee'
+					AND t."date" <= u."date"
+					AND (u.previous_date IS NULL OR t."date" > u.previous_date)
+			), 0) AS fee`,
+		).
+		FromSelect(withPreviousDate, "u")
 	queryBuilder := psql.
 		Select("*").
 		FromSelect(psql.
-			Select("id", `SUM(COALESCE(deposit, 0) - COALESCE(withdrawal, 0)) OVER (PARTITION BY investment_id ORDER BY "date") AS cost`).
-			From("investment_update"), "filtered_data").
+			Select(
+				"id",
+				`SUM(COALESCE(deposit, 0) - COALESCE(withdrawal, 0)) OVER (PARTITION BY investment_id ORDER BY "date") AS cost`,
+				`SUM(fee) OVER (PARTITION BY investment_id ORDER BY "date") AS fees`,
+			).
+			FromSelect(withLedgerFees, "with_ledger_fees"), "filtered_data").
 		Where(sq.Eq{"id": ids})
 
 	query, args, err := queryBuilder.ToSql()
@@ -248,9 +332,9 @@ func (r InvestmentUpdateRepository) calculateCosts(ids []uuid.UUID) (map[uuid.UU
 		return nil, fmt.Errorf("failed to calculate costs: %w", err)
 	}
 
-	costsByID := make(map[uuid.UUID]int64)
+	costsByID := make(map[uuid.UUID]CalculateCostRow)
 	for _, row := range rows {
-		costsByID[row.ID] = row.Cost
+		costsByID[row.ID] = row
 	}
 	return costsByID, nil
 }
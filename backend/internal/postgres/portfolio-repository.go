@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type Portfolio struct {
+	ID        uuid.UUID `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	UserID    string    `db:"user_id"`
+	Name      string    `db:"name"`
+	Currency  string    `db:"currency"`
+}
+
+func (p Portfolio) toDomainPortfolio() domain.Portfolio {
+	return domain.NewPortfolio(p.ID.String(), p.UserID, p.Name, domain.Currency(p.Currency))
+}
+
+type PortfolioRepository struct {
+	db querier
+}
+
+func NewPortfolioRepository(db *sqlx.DB) PortfolioRepository {
+	return PortfolioRepository{db: db}
+}
+
+func (r PortfolioRepository) FindByID(id string) (domain.Portfolio, error) {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return domain.Portfolio{}, domain.ErrPortfolioNotFound
+	}
+
+	entity := Portfolio{}
+	err = r.db.Get(&entity, "SELECT * FROM portfolio WHERE id=$1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Portfolio{}, domain.ErrPortfolioNotFound
+		}
+		return domain.Portfolio{}, fmt.Errorf("failed to select portfolio: %w", err)
+	}
+
+	return entity.toDomainPortfolio(), nil
+}
+
+// FindByUserID finds the portfolios of the user in the order in which they were created.
+func (r PortfolioRepository) FindByUserID(userID string) ([]domain.Portfolio, error) {
+	entities := []Portfolio{}
+	err := r.db.Select(&entities, "SELECT * FROM portfolio WHERE user_id=$1 ORDER BY created_at ASC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select portfolios: %w", err)
+	}
+
+	return slices.Map(entities, func(p Portfolio) domain.Portfolio { return p.toDomainPortfolio() }), nil
+}
+
+func (r PortfolioRepository) Create(userID, name string, currency domain.Currency) (domain.Portfolio, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return domain.Portfolio{}, fmt.Errorf("failed to generate new UUID: %w", err)
+	}
+
+	var entity Portfolio
+	err = r.db.QueryRowx(`
+		INSERT INTO portfolio (id, user_id, "name", currency)
+		VALUES ($1, $2, $3, $4)
+		RETURNING *
+	`, id, userID, name, currency).StructScan(&entity)
+	if err != nil {
+		return domain.Portfolio{}, fmt.Errorf("failed to insert portfolio: %w", err)
+	}
+
+	return entity.toDomainPortfolio(), nil
+}
+
+func (r PortfolioRepository) Update(id, name string, currency domain.Currency) (domain.Portfolio, error) {
+	var entity Portfolio
+	err := r.db.QueryRowx(`
+		UPDATE portfolio
+		SET "name" = $2, currency = $3
+		WHERE id = $1
+		RETURNING *
+	`, id, name, currency).StructScan(&entity)
+	if err != nil {
+		return domain.Portfolio{}, fmt.Errorf("failed to update portfolio: %w", err)
+	}
+
+	return entity.toDomainPortfolio(), nil
+}
+
+func (r PortfolioRepository) DeleteByID(id string) error {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.db.Exec("DELETE FROM portfolio WHERE id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete portfolio: %w", err)
+	}
+
+	return nil
+}
+
+func (r PortfolioRepository) DeleteByUserID(userID string) error {
+	_, err := r.db.Exec("DELETE FROM portfolio WHERE user_id=$1", userID)
+	return err
+}
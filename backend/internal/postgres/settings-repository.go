@@ -11,18 +11,19 @@ import (
 )
 
 type Settings struct {
-	UserID    string    `db:"user_id"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
-	Currency  string    `db:"currency"`
+	UserID         string    `db:"user_id"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+	Currency       string    `db:"currency"`
+	StaleAfterDays int       `db:"stale_after_days"`
 }
 
 func (s Settings) toDomainSettings() domain.Settings {
-	return domain.NewSettings(s.UserID, domain.Currency(s.Currency))
+	return domain.NewSettings(s.UserID, domain.Currency(s.Currency), s.StaleAfterDays)
 }
 
 type SettingsRepository struct {
-	db *sqlx.DB
+	db querier
 }
 
 func NewSettingsRepository(db *sqlx.DB) SettingsRepository {
@@ -45,10 +46,10 @@ func (r SettingsRepository) FindByUserID(userID string) (domain.Settings, error)
 func (r SettingsRepository) Create(settings domain.Settings) (domain.Settings, error) {
 	var entity Settings
 	err := r.db.QueryRowx(`
-		INSERT INTO settings (user_id, currency)
-		VALUES ($1, $2)
+		INSERT INTO settings (user_id, currency, stale_after_days)
+		VALUES ($1, $2, $3)
 		RETURNING *
-	`, settings.UserID, settings.Currency).StructScan(&entity)
+	`, settings.UserID, settings.Currency, settings.StaleAfterDays).StructScan(&entity)
 	if err != nil {
 		return domain.Settings{}, fmt.Errorf("failed to insert settings: %w", err)
 	}
@@ -60,10 +61,10 @@ func (r SettingsRepository) Update(settings domain.Settings) (domain.Settings, e
 	var entity Settings
 	err := r.db.QueryRowx(`
 		UPDATE settings
-		SET currency = $1 
+		SET currency = $1, stale_after_days = $3
 		WHERE user_id = $2
 		RETURNING *;
-	`, settings.Currency, settings.UserID).StructScan(&entity)
+	`, settings.Currency, settings.UserID, settings.StaleAfterDays).StructScan(&entity)
 	if err != nil {
 		return domain.Settings{}, fmt.Errorf("failed to update settings: %w", err)
 	}
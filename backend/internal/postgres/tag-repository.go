@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type Tag struct {
+	ID        uuid.UUID `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	UserID    string    `db:"user_id"`
+	Name      string    `db:"name"`
+}
+
+func (t Tag) toDomainTag() domain.Tag {
+	return domain.NewTag(t.ID.String(), t.UserID, t.Name)
+}
+
+type TagRepository struct {
+	db querier
+}
+
+func NewTagRepository(db *sqlx.DB) TagRepository {
+	return TagRepository{db: db}
+}
+
+func (r TagRepository) FindByID(id string) (domain.Tag, error) {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return domain.Tag{}, domain.ErrTagNotFound
+	}
+
+	entity := Tag{}
+	err = r.db.Get(&entity, "SELECT * FROM tag WHERE id=$1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Tag{}, domain.ErrTagNotFound
+		}
+		return domain.Tag{}, fmt.Errorf("failed to select tag: %w", err)
+	}
+
+	return entity.toDomainTag(), nil
+}
+
+func (r TagRepository) FindByUserID(userID string) ([]domain.Tag, error) {
+	entities := []Tag{}
+	err := r.db.Select(&entities, "SELECT * FROM tag WHERE user_id=$1 ORDER BY name ASC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select tags: %w", err)
+	}
+
+	return slices.Map(entities, func(t Tag) domain.Tag { return t.toDomainTag() }), nil
+}
+
+func (r TagRepository) Create(userID, name string) (domain.Tag, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("failed to generate new UUID: %w", err)
+	}
+
+	var entity Tag
+	err = r.db.QueryRowx(`
+		INSERT INTO tag (id, user_id, "name")
+		VALUES ($1, $2, $3)
+		RETURNING *
+	`, id, userID, name).StructScan(&entity)
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("failed to insert tag: %w", err)
+	}
+
+	return entity.toDomainTag(), nil
+}
+
+func (r TagRepository) UpdateName(id, name string) (domain.Tag, error) {
+	var entity Tag
+	err := r.db.QueryRowx(`
+		UPDATE tag
+		SET "name" = $2
+		WHERE id = $1
+		RETURNING *
+	`, id, name).StructScan(&entity)
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return entity.toDomainTag(), nil
+}
+
+func (r TagRepository) DeleteByID(id string) error {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.db.Exec("DELETE FROM tag WHERE id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r TagRepository) DeleteByUserID(userID string) error {
+	_, err := r.db.Exec("DELETE FROM tag WHERE user_id=$1", userID)
+	return err
+}
+
+func (r TagRepository) ReplaceByInvestmentID(investmentID string, tagIDs []string) error {
+	tx, err := begin(r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM investment_tag WHERE investment_id=$1", investmentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete investment tags: %w", err)
+	}
+
+	for _, tagID := range tagIDs {
+		_, err = tx.Exec("INSERT INTO investment_tag (investment_id, tag_id) VALUES ($1, $2)", investmentID, tagID)
+		if err != nil {
+			return fmt.Errorf("failed to insert investment tag: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
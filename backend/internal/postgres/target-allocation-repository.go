@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type TargetAllocation struct {
+	ID             uuid.UUID  `db:"id"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+	UserID         string     `db:"user_id"`
+	PortfolioID    uuid.UUID  `db:"portfolio_id"`
+	InvestmentType *string    `db:"investment_type"`
+	InvestmentID   *uuid.UUID `db:"investment_id"`
+	Percentage     float64    `db:"percentage"`
+}
+
+func (t TargetAllocation) toDomainTargetAllocation() domain.TargetAllocation {
+	var investmentType *domain.InvestmentType
+	if t.InvestmentType != nil {
+		converted := domain.InvestmentType(*t.InvestmentType)
+		investmentType = &converted
+	}
+
+	var investmentID *string
+	if t.InvestmentID != nil {
+		converted := t.InvestmentID.String()
+		investmentID = &converted
+	}
+
+	return domain.NewTargetAllocation(t.ID.String(), t.UserID, t.PortfolioID.String(), investmentType, investmentID, t.Percentage)
+}
+
+type TargetAllocationRepository struct {
+	db querier
+}
+
+func NewTargetAllocationRepository(db *sqlx.DB) TargetAllocationRepository {
+	return TargetAllocationRepository{db: db}
+}
+
+func (r TargetAllocationRepository) FindByPortfolioID(portfolioID string) ([]domain.TargetAllocation, error) {
+	entities := []TargetAllocation{}
+	err := r.db.Select(&entities, "SELECT * FROM target_allocation WHERE portfolio_id=$1 ORDER BY percentage DESC", portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select target allocations: %w", err)
+	}
+
+	return slices.Map(entities, func(t TargetAllocation) domain.TargetAllocation { return t.toDomainTargetAllocation() }), nil
+}
+
+func (r TargetAllocationRepository) ReplaceByPortfolio(
+	portfolio domain.Portfolio,
+	targets []domain.TargetAllocation,
+) ([]domain.TargetAllocation, error) {
+	tx, err := begin(r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM target_allocation WHERE portfolio_id=$1", portfolio.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete target allocations: %w", err)
+	}
+
+	for _, target := range targets {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate new UUID: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO target_allocation (id, user_id, portfolio_id, investment_type, investment_id, percentage)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, id, portfolio.UserID, portfolio.ID, target.InvestmentType, target.InvestmentID, target.Percentage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert target allocation: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.FindByPortfolioID(portfolio.ID)
+}
+
+func (r TargetAllocationRepository) DeleteByUserID(userID string) error {
+	_, err := r.db.Exec("DELETE FROM target_allocation WHERE user_id=$1", userID)
+	return err
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"growfolio/internal/domain"
+	"growfolio/internal/slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type Transaction struct {
+	ID           uuid.UUID `db:"id"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	Date         time.Time `db:"date"`
+	Type         string    `db:"type"`
+	InvestmentID string    `db:"investment_id"`
+	Amount       int64     `db:"amount"`
+	Units        *float64  `db:"units"`
+	Note         *string   `db:"note"`
+}
+
+func (t Transaction) toDomainTransaction() domain.Transaction {
+	return domain.NewTransaction(
+		t.ID.String(),
+		t.InvestmentID,
+		t.Date,
+		domain.TransactionType(t.Type),
+		t.Amount,
+		t.Units,
+		t.Note,
+	)
+}
+
+type TransactionRepository struct {
+	db querier
+}
+
+func NewTransactionRepository(db *sqlx.DB) TransactionRepository {
+	return TransactionRepository{db: db}
+}
+
+func (r TransactionRepository) FindByID(id string) (domain.Transaction, error) {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return domain.Transaction{}, domain.ErrTransactionNotFound
+	}
+
+	entity := Transaction{}
+	err = r.db.Get(&entity, "SELECT * FROM transaction WHERE id = $1", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Transaction{}, domain.ErrTransactionNotFound
+		}
+		return domain.Transaction{}, fmt.Errorf("failed to select transaction: %w", err)
+	}
+
+	return entity.toDomainTransaction(), nil
+}
+
+func (r TransactionRepository) Find(findQuery domain.FindTransactionQuery) ([]domain.Transaction, error) {
+	queryBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("*").
+		From("transaction").
+		Where(sq.Eq{"investment_id": findQuery.InvestmentIDs})
+
+	if len(findQuery.Types) > 0 {
+		types := slices.Map(findQuery.Types, func(t domain.TransactionType) string { return string(t) })
+		queryBuilder = queryBuilder.Where(sq.Eq{"type": types})
+	}
+	if findQuery.DateFrom != nil {
+		queryBuilder = queryBuilder.Where(sq.Expr("date >= ?", *findQuery.DateFrom))
+	}
+	if findQuery.DateTo != nil {
+		queryBuilder = queryBuilder.Where(sq.Expr("date <= ?", *findQuery.DateTo))
+	}
+
+	queryBuilder = queryBuilder.OrderBy("date ASC", "created_at ASC")
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	entities := []Transaction{}
+	err = r.db.Select(&entities, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select transactions: %w", err)
+	}
+
+	return slices.Map(entities, func(t Transaction) domain.Transaction { return t.toDomainTransaction() }), nil
+}
+
+func (r TransactionRepository) Create(c domain.SaveTransactionCommand) (domain.Transaction, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to generate new UUID: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO transaction (id, investment_id, "date", "type", amount, units, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, id, c.Investment.ID, c.Date, c.Type, c.Amount, c.Units, c.Note)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to insert transaction: %w", err)
+	}
+
+	return r.FindByID(id.String())
+}
+
+func (r TransactionRepository) Update(id string, c domain.SaveTransactionCommand) (domain.Transaction, error) {
+	_, err := r.db.Exec(`
+		UPDATE transaction
+		SET "date" = $2, "type" = $3, amount = $4, units = $5, note = $6
+		WHERE id = $1
+	`, id, c.Date, c.Type, c.Amount, c.Units, c.Note)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	return r.FindByID(id)
+}
+
+func (r TransactionRepository) DeleteByID(id string) error {
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.db.Exec("DELETE FROM transaction WHERE id=$1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r TransactionRepository) DeleteByInvestmentID(investmentID string) error {
+	_, err := uuid.Parse(investmentID)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.db.Exec("DELETE FROM transaction WHERE investment_id=$1", investmentID)
+	return err
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"growfolio/internal/domain/services"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// querier runs queries on either the database or a transaction, so a repository can take part in a unit of work.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Get(dest any, query string, args ...any) error
+	Select(dest any, query string, args ...any) error
+	QueryRowx(query string, args ...any) *sqlx.Row
+}
+
+type transaction interface {
+	querier
+	Commit() error
+	Rollback() error
+}
+
+// begin starts a transaction on the database. Within a unit of work, the queries join the transaction of the unit of
+// work instead, which is only committed or rolled back by the unit of work itself.
+func begin(q querier) (transaction, error) {
+	switch v := q.(type) {
+	case *sqlx.DB:
+		tx, err := v.Beginx()
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
+	case *sqlx.Tx:
+		return joinedTransaction{Tx: v}, nil
+	}
+	return nil, fmt.Errorf("unsupported querier %T", q)
+}
+
+type joinedTransaction struct {
+	*sqlx.Tx
+}
+
+func (t joinedTransaction) Commit() error {
+	return nil
+}
+
+func (t joinedTransaction) Rollback() error {
+	return nil
+}
+
+type UnitOfWork struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWork(db *sqlx.DB) UnitOfWork {
+	return UnitOfWork{db: db}
+}
+
+func (u UnitOfWork) Do(f func(repositories services.Repositories) error) error {
+	tx, err := u.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = f(newRepositories(tx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func newRepositories(tx *sqlx.Tx) services.Repositories {
+	investmentUpdateRepository := InvestmentUpdateRepository{db: tx}
+
+	return services.Repositories{
+		User:             UserRepository{db: tx},
+		Settings:         SettingsRepository{db: tx},
+		Portfolio:        PortfolioRepository{db: tx},
+		Investment:       InvestmentRepository{db: tx, investmentUpdateRepository: investmentUpdateRepository},
+		InvestmentUpdate: investmentUpdateRepository,
+		Transaction:      TransactionRepository{db: tx},
+		TargetAllocation: TargetAllocationRepository{db: tx},
+		Tag:              TagRepository{db: tx},
+		Goal:             GoalRepository{db: tx},
+		ImportProfile:    ImportProfileRepository{db: tx},
+		ContributionPlan: ContributionPlanRepository{db: tx},
+	}
+}
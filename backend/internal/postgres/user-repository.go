@@ -35,7 +35,7 @@ func (u User) toDomainUser() domain.User {
 }
 
 type UserRepository struct {
-	db *sqlx.DB
+	db querier
 }
 
 func NewUserRepository(db *sqlx.DB) UserRepository {
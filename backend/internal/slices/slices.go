@@ -40,3 +40,13 @@ func Deduplicate[T comparable](input []T) []T {
 
 	return n
 }
+
+func Filter[T any](a []T, f func(T) bool) []T {
+	n := make([]T, 0)
+	for _, e := range a {
+		if f(e) {
+			n = append(n, e)
+		}
+	}
+	return n
+}
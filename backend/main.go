@@ -58,11 +58,20 @@ func main() {
 		log.Fatal("Failed to migrate the database: ", err)
 	}
 
+	unitOfWork := postgres.NewUnitOfWork(db)
 	investmentUpdateRepository := postgres.NewInvestmentUpdateRepository(db)
 	investmentRepository := postgres.NewInvestmentRepository(db, investmentUpdateRepository)
-	investmentUpdateService := services.NewInvestmentUpdateService(investmentUpdateRepository)
+	investmentUpdateService := services.NewInvestmentUpdateService(investmentUpdateRepository, unitOfWork)
 	userRepository := postgres.NewUserRepository(db)
 	settingsRepository := postgres.NewSettingsRepository(db)
+	targetAllocationRepository := postgres.NewTargetAllocationRepository(db)
+	fxRateRepository := postgres.NewFXRateRepository(db)
+	transactionRepository := postgres.NewTransactionRepository(db)
+	tagRepository := postgres.NewTagRepository(db)
+	portfolioRepository := postgres.NewPortfolioRepository(db)
+	goalRepository := postgres.NewGoalRepository(db)
+	contributionPlanRepository := postgres.NewContributionPlanRepository(db)
+	importProfileRepository := postgres.NewImportProfileRepository(db)
 
 	eventHandlers := []services.EventHandler{
 		discord.NewDiscordEventHandler(os.Getenv("DISCORD_BOT_TOKEN"), os.Getenv("DISCORD_EVENT_CHANNEL_ID")),
@@ -76,13 +85,92 @@ func main() {
 		mustParseBool(os.Getenv("USE_SECURE_COOKIES")),
 	)
 	settingsService := services.NewSettingsService(settingsRepository)
-	investmentService := services.NewInvestmentService(investmentRepository, investmentUpdateService)
-	userService := services.NewUserService(userRepository, investmentService, eventPublisher, settingsService)
+	fxRateService := services.NewFXRateService(fxRateRepository)
+	transactionService := services.NewTransactionService(transactionRepository, investmentUpdateService)
+	investmentService := services.NewInvestmentService(
+		investmentRepository,
+		investmentUpdateService,
+		eventPublisher,
+		unitOfWork,
+	)
+	targetAllocationService := services.NewTargetAllocationService(targetAllocationRepository, investmentService, fxRateService)
+	tagService := services.NewTagService(tagRepository)
+	importProfileService := services.NewImportProfileService(importProfileRepository)
+	portfolioService := services.NewPortfolioService(
+		portfolioRepository,
+		investmentUpdateService,
+		fxRateService,
+		settingsService,
+		unitOfWork,
+	)
+	goalService := services.NewGoalService(
+		goalRepository,
+		investmentService,
+		investmentUpdateService,
+		fxRateService,
+		settingsService,
+		tagService,
+		eventPublisher,
+	)
+	userService := services.NewUserService(
+		userRepository,
+		investmentService,
+		eventPublisher,
+		portfolioService,
+		unitOfWork,
+	)
+	contributionPlanService := services.NewContributionPlanService(
+		contributionPlanRepository,
+		investmentService,
+		unitOfWork,
+	)
 	demoUserCleaner := services.NewDemoUserCleaner(userService)
+	goalChecker := services.NewGoalChecker(goalService)
+	staleInvestmentChecker := services.NewStaleInvestmentChecker(investmentService, settingsService)
+	contributionPlanRunner := services.NewContributionPlanRunner(contributionPlanService)
 	investmentUpdateCSVImporter := api.NewInvestmentUpdateCSVImporter(investmentUpdateService)
-
-	investmentHandler := api.NewInvestmentHandler(investmentService, investmentUpdateService, &userRepository, investmentUpdateCSVImporter)
-	investmentUpdateHandler := api.NewInvestmentUpdateHandler(investmentService, investmentUpdateService)
+	statementImporter := api.NewInvestmentUpdateStatementImporter(
+		investmentUpdateService,
+		map[string]api.StatementImporter{
+			"degiro":     api.NewDEGIROStatementImporter(),
+			"ibkr-flex":  api.NewIBKRFlexStatementImporter(),
+			"trading212": api.NewTrading212StatementImporter(),
+		},
+	)
+	fxRateCSVImporter := api.NewFXRateCSVImporter(fxRateService)
+	holdingService := services.NewHoldingService(investmentUpdateService)
+	feeService := services.NewFeeService(investmentUpdateService, transactionService)
+	gainService := services.NewGainService(investmentUpdateService)
+	returnService := services.NewReturnService(investmentUpdateService, fxRateService)
+
+	investmentHandler := api.NewInvestmentHandler(
+		investmentService,
+		investmentUpdateService,
+		transactionService,
+		holdingService,
+		portfolioService,
+		settingsService,
+		importProfileService,
+		&userRepository,
+		investmentUpdateCSVImporter,
+		statementImporter,
+	)
+	investmentUpdateHandler := api.NewInvestmentUpdateHandler(investmentService, investmentUpdateService, portfolioService)
+	transactionHandler := api.NewTransactionHandler(investmentService, transactionService, portfolioService)
+	returnHandler := api.NewReturnHandler(investmentService, returnService, portfolioService, tagService)
+	portfolioHandler := api.NewPortfolioHandler(
+		investmentService,
+		portfolioService,
+		feeService,
+		gainService,
+		tagService,
+	)
+	targetAllocationHandler := api.NewTargetAllocationHandler(targetAllocationService, portfolioService)
+	tagHandler := api.NewTagHandler(tagService, investmentService)
+	goalHandler := api.NewGoalHandler(goalService)
+	contributionPlanHandler := api.NewContributionPlanHandler(contributionPlanService, investmentService, portfolioService)
+	importProfileHandler := api.NewImportProfileHandler(importProfileService)
+	fxRateHandler := api.NewFXRateHandler(fxRateCSVImporter)
 	authHandler := api.NewAuthHandler(userService, tokenService)
 	userHandler := api.NewUserHandler(&userRepository)
 	settingsHandler := api.NewSettingsHandler(settingsService)
@@ -95,11 +183,26 @@ func main() {
 		os.Getenv("FRONTEND_HOST"),
 	)
 	contactHandler := api.NewContactHandler(os.Getenv("DISCORD_BOT_TOKEN"), os.Getenv("DISCORD_CONTACT_CHANNEL_ID"))
-	demoHandler := api.NewDemoHandler(userService, investmentService, investmentUpdateCSVImporter, tokenService)
+	demoHandler := api.NewDemoHandler(
+		userService,
+		investmentService,
+		portfolioService,
+		investmentUpdateCSVImporter,
+		tokenService,
+	)
 
 	handlers := api.NewHandlers(
 		investmentHandler,
 		investmentUpdateHandler,
+		transactionHandler,
+		returnHandler,
+		portfolioHandler,
+		targetAllocationHandler,
+		tagHandler,
+		goalHandler,
+		contributionPlanHandler,
+		importProfileHandler,
+		fxRateHandler,
 		authHandler,
 		userHandler,
 		settingsHandler,
@@ -108,7 +211,7 @@ func main() {
 		contactHandler,
 		demoHandler,
 	)
-	middlewares := api.NewMiddlewares(api.TokenMiddleware(tokenService))
+	middlewares := api.NewMiddlewares(api.TokenMiddleware(tokenService), api.AdminMiddleware(os.Getenv("ADMIN_API_KEY")))
 	server := api.NewServer(
 		os.Getenv("GOOGLE_CLIENT_ID"),
 		os.Getenv("GOOGLE_CLIENT_SECRET"),
@@ -119,7 +222,10 @@ func main() {
 	)
 
 	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
-	c.AddFunc("0 0 * * * *", demoUserCleaner.Clean) // every hour
+	c.AddFunc("0 0 * * * *", demoUserCleaner.Clean)        // every hour
+	c.AddFunc("0 0 6 * * *", goalChecker.Check)            // every day at 6
+	c.AddFunc("0 0 5 * * *", contributionPlanRunner.Run)   // every day at 5
+	c.AddFunc("0 0 7 * * *", staleInvestmentChecker.Check) // every day at 7
 	c.Start()
 
 	log.Fatal(server.Start(8888))
@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS target_allocation(
+    id UUID NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    user_id TEXT NOT NULL REFERENCES "user" (id),
+    investment_type TEXT,
+    investment_id UUID REFERENCES investment (id) ON DELETE CASCADE,
+    percentage DOUBLE PRECISION NOT NULL,
+    CHECK ((investment_type IS NULL) <> (investment_id IS NULL)),
+    UNIQUE (user_id, investment_type),
+    UNIQUE (user_id, investment_id)
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE
+    ON target_allocation
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_updated_at();
+
+COMMIT;
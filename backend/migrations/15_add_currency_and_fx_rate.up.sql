@@ -0,0 +1,25 @@
+BEGIN;
+
+ALTER TABLE investment ADD COLUMN currency TEXT;
+
+UPDATE investment
+SET currency = COALESCE((SELECT s.currency FROM settings s WHERE s.user_id = investment.user_id), 'USD');
+
+ALTER TABLE investment ALTER COLUMN currency SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS fx_rate(
+    "date" DATE NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    rate DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY ("date", currency)
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE
+    ON fx_rate
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_updated_at();
+
+COMMIT;
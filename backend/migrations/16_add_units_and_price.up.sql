@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE investment_update ADD COLUMN units DOUBLE PRECISION;
+ALTER TABLE investment_update ADD COLUMN price DOUBLE PRECISION;
+
+COMMIT;
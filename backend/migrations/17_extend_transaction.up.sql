@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE transaction ADD COLUMN units DOUBLE PRECISION;
+ALTER TABLE transaction ADD COLUMN note TEXT;
+
+CREATE INDEX idx_transaction_investment_id_date ON transaction(investment_id, "date");
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE investment_update ADD COLUMN income BIGINT;
+
+COMMIT;
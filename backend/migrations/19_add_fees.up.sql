@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE investment_update ADD COLUMN fee BIGINT;
+ALTER TABLE investment ADD COLUMN annual_fee_percentage DOUBLE PRECISION;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE investment ADD COLUMN display_order INTEGER;
+
+UPDATE investment
+SET display_order = ordered.display_order
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at) - 1 AS display_order
+    FROM investment
+) AS ordered
+WHERE investment.id = ordered.id;
+
+ALTER TABLE investment ALTER COLUMN display_order SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE investment ADD COLUMN closed_at DATE;
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS tag(
+    id UUID NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    user_id TEXT NOT NULL REFERENCES "user" (id),
+    "name" TEXT NOT NULL,
+    UNIQUE (user_id, "name")
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE
+    ON tag
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_updated_at();
+
+CREATE TABLE IF NOT EXISTS investment_tag(
+    investment_id UUID NOT NULL REFERENCES investment (id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tag (id) ON DELETE CASCADE,
+    PRIMARY KEY (investment_id, tag_id)
+);
+
+CREATE INDEX idx_investment_tag_tag_id ON investment_tag(tag_id);
+
+COMMIT;
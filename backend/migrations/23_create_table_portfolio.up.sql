@@ -0,0 +1,49 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS portfolio(
+    id UUID NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    user_id TEXT NOT NULL REFERENCES "user" (id),
+    "name" TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    UNIQUE (user_id, "name")
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE
+    ON portfolio
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_updated_at();
+
+INSERT INTO portfolio (id, user_id, "name", currency)
+SELECT gen_random_uuid(), "user".id, 'Default', COALESCE(settings.currency, 'USD')
+FROM "user"
+LEFT JOIN settings ON settings.user_id = "user".id;
+
+ALTER TABLE investment ADD COLUMN portfolio_id UUID REFERENCES portfolio (id);
+
+UPDATE investment
+SET portfolio_id = portfolio.id
+FROM portfolio
+WHERE portfolio.user_id = investment.user_id;
+
+ALTER TABLE investment ALTER COLUMN portfolio_id SET NOT NULL;
+
+CREATE INDEX idx_investment_portfolio_id ON investment(portfolio_id);
+
+ALTER TABLE target_allocation ADD COLUMN portfolio_id UUID REFERENCES portfolio (id) ON DELETE CASCADE;
+
+UPDATE target_allocation
+SET portfolio_id = portfolio.id
+FROM portfolio
+WHERE portfolio.user_id = target_allocation.user_id;
+
+ALTER TABLE target_allocation ALTER COLUMN portfolio_id SET NOT NULL;
+
+ALTER TABLE target_allocation DROP CONSTRAINT target_allocation_user_id_investment_type_key;
+ALTER TABLE target_allocation DROP CONSTRAINT target_allocation_user_id_investment_id_key;
+ALTER TABLE target_allocation ADD UNIQUE (portfolio_id, investment_type);
+ALTER TABLE target_allocation ADD UNIQUE (portfolio_id, investment_id);
+
+COMMIT;
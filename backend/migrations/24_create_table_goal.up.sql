@@ -0,0 +1,34 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS goal(
+    id UUID NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    user_id TEXT NOT NULL REFERENCES "user" (id),
+    "name" TEXT NOT NULL,
+    target_amount BIGINT NOT NULL,
+    target_date DATE NOT NULL,
+    currency TEXT NOT NULL,
+    expected_return_percentage DOUBLE PRECISION NOT NULL,
+    status TEXT
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE
+    ON goal
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_updated_at();
+
+CREATE TABLE IF NOT EXISTS goal_investment(
+    goal_id UUID NOT NULL REFERENCES goal (id) ON DELETE CASCADE,
+    investment_id UUID NOT NULL REFERENCES investment (id) ON DELETE CASCADE,
+    PRIMARY KEY (goal_id, investment_id)
+);
+
+CREATE TABLE IF NOT EXISTS goal_tag(
+    goal_id UUID NOT NULL REFERENCES goal (id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tag (id) ON DELETE CASCADE,
+    PRIMARY KEY (goal_id, tag_id)
+);
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS contribution_plan(
+    id UUID NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    investment_id UUID NOT NULL REFERENCES investment (id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL,
+    frequency TEXT NOT NULL,
+    day_of_month INTEGER NOT NULL CHECK (day_of_month BETWEEN 1 AND 31),
+    next_run_date DATE NOT NULL,
+    paused BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE
+    ON contribution_plan
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_updated_at();
+
+CREATE INDEX idx_contribution_plan_next_run_date ON contribution_plan(next_run_date) WHERE NOT paused;
+
+CREATE TABLE IF NOT EXISTS contribution_plan_skipped_month(
+    contribution_plan_id UUID NOT NULL REFERENCES contribution_plan (id) ON DELETE CASCADE,
+    "month" DATE NOT NULL,
+    PRIMARY KEY (contribution_plan_id, "month")
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE settings ADD COLUMN IF NOT EXISTS stale_after_days INTEGER NOT NULL DEFAULT 30;
+
+ALTER TABLE investment ADD COLUMN IF NOT EXISTS stale_after_days INTEGER;
+ALTER TABLE investment ADD COLUMN IF NOT EXISTS stale_reminded_at DATE;
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+-- merges the updates that share an investment and a date into the most recently updated one, which keeps its value,
+-- units and price, while the deposits, withdrawals, income and fees of all of them are added up
+CREATE TEMPORARY TABLE investment_update_duplicate ON COMMIT DROP AS
+SELECT investment_id,
+       "date",
+       (ARRAY_AGG(id ORDER BY updated_at DESC, created_at DESC, id DESC))[1] AS kept_id,
+       SUM(deposit)    AS deposit,
+       SUM(withdrawal) AS withdrawal,
+       SUM(income)     AS income,
+       SUM(fee)        AS fee
+FROM investment_update
+GROUP BY investment_id, "date"
+HAVING COUNT(*) > 1;
+
+UPDATE investment_update
+SET deposit    = investment_update_duplicate.deposit,
+    withdrawal = investment_update_duplicate.withdrawal,
+    income     = investment_update_duplicate.income,
+    fee        = investment_update_duplicate.fee
+FROM investment_update_duplicate
+WHERE investment_update.id = investment_update_duplicate.kept_id;
+
+DELETE FROM investment_update
+USING investment_update_duplicate
+WHERE investment_update.investment_id = investment_update_duplicate.investment_id
+  AND investment_update."date" = investment_update_duplicate."date"
+  AND investment_update.id <> investment_update_duplicate.kept_id;
+
+ALTER TABLE investment_update
+    ADD CONSTRAINT investment_update_investment_id_date_key UNIQUE (investment_id, "date");
+
+COMMIT;
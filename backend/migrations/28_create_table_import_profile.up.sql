@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS import_profile(
+    id UUID NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    user_id TEXT NOT NULL REFERENCES "user" (id),
+    "name" TEXT NOT NULL,
+    delimiter TEXT NOT NULL,
+    date_format TEXT NOT NULL,
+    decimal_separator TEXT NOT NULL,
+    thousands_separator TEXT NOT NULL,
+    amount_scale TEXT NOT NULL,
+    UNIQUE (user_id, "name")
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE
+    ON import_profile
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_updated_at();
+
+CREATE TABLE IF NOT EXISTS import_profile_column(
+    import_profile_id UUID NOT NULL REFERENCES import_profile (id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    header TEXT NOT NULL,
+    PRIMARY KEY (import_profile_id, field)
+);
+
+COMMIT;